@@ -0,0 +1,60 @@
+package udotenv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+const (
+	ageExt    = ".age"
+	ageKeyEnv = "UDOTENV_AGE_KEY"
+)
+
+var ageArmorHeader = []byte(armor.Header)
+
+// decryptAge returns a reader of the plaintext of the age-encrypted content
+// read from r, which may be armored. The plaintext stays in memory.
+func (ue *UdotEnv) decryptAge(r io.Reader) (io.Reader, error) {
+	identities, err := ue.ageIdentities()
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if header, _ := br.Peek(len(ageArmorHeader)); bytes.Equal(header, ageArmorHeader) {
+		src = armor.NewReader(br)
+	}
+
+	plain, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting age file: %w", err)
+	}
+	return plain, nil
+}
+
+// ageIdentities returns the identities of the `AgeIdentityFile` of the
+// config or, if it is not set, of the UDOTENV_AGE_KEY variable.
+func (ue *UdotEnv) ageIdentities() ([]age.Identity, error) {
+	if ue.Config != nil && ue.Config.AgeIdentityFile != "" {
+		f, err := os.Open(ue.Config.AgeIdentityFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading age identity: %w", err)
+		}
+		defer f.Close()
+		return age.ParseIdentities(f)
+	}
+
+	if key := os.Getenv(ageKeyEnv); key != "" {
+		return age.ParseIdentities(strings.NewReader(key))
+	}
+	return nil, errors.New("no age identity, set AgeIdentityFile in the config or " + ageKeyEnv)
+}
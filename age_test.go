@@ -0,0 +1,75 @@
+package udotenv
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/assert"
+)
+
+func encryptAge(t *testing.T, recipient age.Recipient, plain string, armored bool) []byte {
+	var buf bytes.Buffer
+	var out io.Writer = &buf
+	var a io.WriteCloser
+	if armored {
+		a = armor.NewWriter(&buf)
+		out = a
+	}
+
+	w, err := age.Encrypt(out, recipient)
+	assert.NoError(t, err)
+	_, _ = io.WriteString(w, plain)
+	assert.NoError(t, w.Close())
+	if a != nil {
+		assert.NoError(t, a.Close())
+	}
+	return buf.Bytes()
+}
+
+func TestLoad_Age(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.txt")
+	_ = os.WriteFile(keyFile, []byte(identity.String()+"\n"), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, ".env.age"), encryptAge(t, identity.Recipient(), "AGE_A=1\n", false), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "armored.env.age"), encryptAge(t, identity.Recipient(), "AGE_B=2\n", true), 0o644)
+	defer os.Unsetenv("AGE_A")
+	defer os.Unsetenv("AGE_B")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{AgeIdentityFile: keyFile},
+		EnvParam: stringSlice{filepath.Join(dir, ".env.age"), filepath.Join(dir, "armored.env.age")},
+	}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "1", os.Getenv("AGE_A"))
+	assert.Equal(t, "2", os.Getenv("AGE_B"))
+}
+
+func TestLoad_AgeKeyFromEnv(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+	other, err := age.GenerateX25519Identity()
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), ".env.age")
+	_ = os.WriteFile(path, encryptAge(t, identity.Recipient(), "AGE_ENV=1\n", false), 0o644)
+	defer os.Unsetenv("AGE_ENV")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.EqualError(t, udotEnv.Load(),
+		"error loading file '"+path+"': no age identity, set AgeIdentityFile in the config or UDOTENV_AGE_KEY")
+
+	t.Setenv(ageKeyEnv, other.String())
+	assert.ErrorContains(t, udotEnv.Load(), "decrypting age file: no identity matched any of the recipients")
+
+	t.Setenv(ageKeyEnv, identity.String())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "1", os.Getenv("AGE_ENV"))
+}
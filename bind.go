@@ -0,0 +1,199 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// fieldTag is the parsed `env` tag of a struct field.
+type fieldTag struct {
+	name       string
+	required   bool
+	def        string
+	hasDefault bool
+}
+
+// parseFieldTag parses tags of the form `env:"NAME,required,default=VALUE"`.
+// The default takes the rest of the tag, so it may contain commas.
+func parseFieldTag(tag string) fieldTag {
+	name, rest, _ := strings.Cut(tag, ",")
+	ft := fieldTag{name: name}
+	for rest != "" {
+		var opt string
+		if strings.HasPrefix(rest, "default=") {
+			ft.def, ft.hasDefault = strings.TrimPrefix(rest, "default="), true
+			break
+		}
+
+		opt, rest, _ = strings.Cut(rest, ",")
+		if opt == "required" {
+			ft.required = true
+		}
+	}
+	return ft
+}
+
+// Bind sets the fields of the struct pointed to by target from the
+// environment. Fields are matched through their `env` tag:
+//
+//	type Config struct {
+//	    Port    int           `env:"PORT,default=8080"`
+//	    DSN     string        `env:"DATABASE_URL,required"`
+//	    Timeout time.Duration `env:"TIMEOUT,default=5s"`
+//	    Cache   struct {
+//	        Host string `env:"HOST"`
+//	    } `envPrefix:"CACHE_"`
+//	}
+//
+// Nested structs are bound recursively, with the `envPrefix` tag prepended to
+// the names of their fields. Values are taken from the variables loaded by the
+// last call to Load, then from the environment. Supported field types are
+// the ones of Get: strings, booleans, integers, floats, time.Duration,
+// time.Time, url.URL, the types of RegisterDecoder, the types implementing
+// encoding.TextUnmarshaler, slices and maps of them and pointers to them.
+// The `envSeparator` tag replaces the `ListSeparator` of the config for a
+// field, and the `envKeyValSeparator` tag its `KeyValueSeparator`:
+//
+//	Labels map[string]string `env:"LABELS" envSeparator:";" envKeyValSeparator:"="`
+//
+// All the missing required keys and invalid values are reported in the
+// returned error.
+func (ue *UdotEnv) Bind(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("bind target must be a non-nil pointer to a struct")
+	}
+	return ue.bindStruct(v.Elem(), "")
+}
+
+func (ue *UdotEnv) bindStruct(v reflect.Value, prefix string) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok {
+			if isNestedStruct(field.Type) {
+				errs = append(errs, ue.bindStruct(v.Field(i), prefix+field.Tag.Get("envPrefix")))
+			}
+			continue
+		}
+
+		ft := parseFieldTag(tag)
+		key := prefix + ft.name
+		value, found := ue.bindValue(key)
+		if !found && ft.hasDefault {
+			value, found = ft.def, true
+		}
+
+		if !found {
+			if ft.required {
+				errs = append(errs, fmt.Errorf("%s: required key is missing", key))
+			}
+			continue
+		}
+
+		seps := ue.separators()
+		if sep, ok := field.Tag.Lookup("envSeparator"); ok {
+			seps.list = sep
+		}
+		if sep, ok := field.Tag.Lookup("envKeyValSeparator"); ok {
+			seps.keyValue = sep
+		}
+		if err := decodeInto(v.Field(i), value, seps); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, ue.maskError(key, value, err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bindValue returns the value of key from the loaded variables or, failing
+// that, from the environment.
+func (ue *UdotEnv) bindValue(key string) (string, bool) {
+	ue.mu.RLock()
+	v, ok := ue.vars[key]
+	ue.mu.RUnlock()
+	if ok {
+		return v, true
+	}
+	return os.LookupEnv(key)
+}
+
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return false
+	}
+	_, ok := lookupDecoder(t)
+	return !ok
+}
+
+// decodeInto converts s to the type of v and stores it in v, splitting the
+// slices and maps with seps.
+func decodeInto(v reflect.Value, s string, seps separators) error {
+	if v.Kind() == reflect.Pointer {
+		p := reflect.New(v.Type().Elem())
+		if err := decodeInto(p.Elem(), s, seps); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+
+	if decode, ok := lookupDecoder(v.Type()); ok {
+		return decodeWith(v, s, decode)
+	}
+	if u, ok := textUnmarshaler(v); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(stripUnderscores(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(stripUnderscores(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(stripUnderscores(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		return decodeSlice(v, s, seps)
+	case reflect.Map:
+		return decodeMap(v, s, seps)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package udotenv
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "BIND_PORT=9090\n"+
+		"BIND_DEBUG=on\n"+
+		"BIND_RATIO=0.5\n"+
+		"BIND_DB_HOST=db\n"+
+		"BIND_DB_PORT=5432\n"+
+		"BIND_URL=https://example.com\n")
+
+	os.Setenv("BIND_FROM_ENV", "env")
+	defer os.Unsetenv("BIND_FROM_ENV")
+
+	var cfg struct {
+		Port    int           `env:"BIND_PORT,required"`
+		Debug   bool          `env:"BIND_DEBUG"`
+		Ratio   float64       `env:"BIND_RATIO"`
+		Timeout time.Duration `env:"BIND_TIMEOUT,default=5s"`
+		List    string        `env:"BIND_LIST,default=a,b"`
+		FromEnv string        `env:"BIND_FROM_ENV"`
+		Name    *string       `env:"BIND_URL"`
+		Missing *string       `env:"BIND_MISSING"`
+		DB      struct {
+			Host string `env:"HOST"`
+			Port uint16 `env:"PORT"`
+		} `envPrefix:"BIND_DB_"`
+		untagged string
+	}
+
+	assert.NoError(t, udotEnv.Bind(&cfg))
+	assert.Equal(t, 9090, cfg.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 0.5, cfg.Ratio)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, "a,b", cfg.List)
+	assert.Equal(t, "env", cfg.FromEnv)
+	assert.Equal(t, "https://example.com", *cfg.Name)
+	assert.Nil(t, cfg.Missing)
+	assert.Equal(t, "db", cfg.DB.Host)
+	assert.Equal(t, uint16(5432), cfg.DB.Port)
+	assert.Empty(t, cfg.untagged)
+}
+
+func TestBind_Errors(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "BIND_BAD_PORT=http\n")
+
+	var cfg struct {
+		Port int    `env:"BIND_BAD_PORT"`
+		DSN  string `env:"BIND_BAD_DSN,required"`
+	}
+
+	err := udotEnv.Bind(&cfg)
+	assert.ErrorContains(t, err, "BIND_BAD_PORT: ")
+	assert.ErrorContains(t, err, "BIND_BAD_DSN: required key is missing")
+
+	assert.Error(t, udotEnv.Bind(cfg))
+}
@@ -0,0 +1,91 @@
+package udotenv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ByteSize is a number of bytes, decoded by Get and Bind from humanized sizes
+// such as "25MB", "1.5 GiB" or "512". The decimal units, KB to PB, are powers
+// of 1000 and the binary ones, KiB to PiB, powers of 1024. Units ignore case
+// and K, M, G, T and P stand for the decimal ones:
+//
+//	maxUpload, err := udotenv.Get[udotenv.ByteSize](udotEnv, "MAX_UPLOAD")
+type ByteSize int64
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// ParseByteSize parses a humanized size such as "25MB" into a number of
+// bytes. See ByteSize.
+func ParseByteSize(s string) (int64, error) {
+	num := strings.TrimSpace(s)
+	i := strings.IndexFunc(num, unicode.IsLetter)
+	unit := ""
+	if i >= 0 {
+		num, unit = strings.TrimSpace(num[:i]), strings.ToLower(num[i:])
+	}
+
+	mult, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unit)
+	}
+	f, err := strconv.ParseFloat(stripUnderscores(num), 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	size := math.Round(f * mult)
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q out of range", s)
+	}
+	return int64(size), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	n, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(n)
+	return nil
+}
+
+// stripUnderscores removes the underscores separating the digits of s, as in
+// "1_000_000". Other underscores are kept, for the parsing to fail.
+func stripUnderscores(s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+	for i := range len(s) {
+		if s[i] == '_' && (i == 0 || i == len(s)-1 || !isDigit(s[i-1]) || !isDigit(s[i+1])) {
+			return s
+		}
+	}
+	return strings.ReplaceAll(s, "_", "")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
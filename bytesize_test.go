@@ -0,0 +1,71 @@
+package udotenv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for s, want := range map[string]int64{
+		"512":       512,
+		"512B":      512,
+		"25MB":      25_000_000,
+		"25mb":      25_000_000,
+		"10 KB":     10_000,
+		"2k":        2_000,
+		"1.5GiB":    3 << 29,
+		"4KiB":      4096,
+		"1_000_000": 1_000_000,
+		" 1TB ":     1e12,
+	} {
+		n, err := ParseByteSize(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, n, s)
+	}
+
+	for _, s := range []string{"", "MB", "-1MB", "10XB", "1__0", "_10", "1e30PB"} {
+		_, err := ParseByteSize(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestStripUnderscores(t *testing.T) {
+	assert.Equal(t, "1000000", stripUnderscores("1_000_000"))
+	assert.Equal(t, "1.5", stripUnderscores("1.5"))
+	assert.Equal(t, "_1", stripUnderscores("_1"))
+	assert.Equal(t, "1__0", stripUnderscores("1__0"))
+	assert.Equal(t, "1_", stripUnderscores("1_"))
+}
+
+func TestGet_HumanNumbers(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "HUMAN_MAX_UPLOAD=25MB\n"+
+		"HUMAN_COUNT=1_000_000\n"+
+		"HUMAN_RATIO=0.000_5\n"+
+		"HUMAN_CACHE_TTL=2h30m\n"+
+		"HUMAN_BAD_SIZE=25XB\n")
+
+	size, err := Get[ByteSize](udotEnv, "HUMAN_MAX_UPLOAD")
+	assert.NoError(t, err)
+	assert.Equal(t, ByteSize(25_000_000), size)
+
+	count, err := Get[int64](udotEnv, "HUMAN_COUNT")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1_000_000), count)
+
+	var cfg struct {
+		MaxUpload ByteSize      `env:"HUMAN_MAX_UPLOAD"`
+		Count     uint          `env:"HUMAN_COUNT"`
+		Ratio     float64       `env:"HUMAN_RATIO"`
+		CacheTTL  time.Duration `env:"HUMAN_CACHE_TTL"`
+	}
+	assert.NoError(t, udotEnv.Bind(&cfg))
+	assert.Equal(t, ByteSize(25_000_000), cfg.MaxUpload)
+	assert.Equal(t, uint(1_000_000), cfg.Count)
+	assert.Equal(t, 0.0005, cfg.Ratio)
+	assert.Equal(t, 150*time.Minute, cfg.CacheTTL)
+
+	_, err = Get[ByteSize](udotEnv, "HUMAN_BAD_SIZE")
+	assert.ErrorContains(t, err, `unknown unit "xb"`)
+}
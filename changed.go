@@ -0,0 +1,82 @@
+package udotenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// fileStamp describes the state of a file at the moment it was loaded.
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// stampFile captures the current state of the file at path. A missing file is
+// not an error, it produces a stamp with `exists` set to false.
+func stampFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fileStamp{}, nil
+	} else if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{exists: true, size: info.Size(), modTime: info.ModTime()}, nil
+}
+
+// stampFiles captures the state of every file in paths. Remote files are
+// skipped.
+func stampFiles(paths []string) (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		if isRemote(path) {
+			continue
+		}
+		stamp, err := stampFile(path)
+		if err != nil {
+			return nil, err
+		}
+		stamps[path] = stamp
+	}
+	return stamps, nil
+}
+
+// Changed reports whether any of the env files, or of the files they include,
+// changed since the last call to Load. Files are compared by size and
+// modification time; a file that was deleted or created since the last load
+// counts as changed. Remote files are not checked. If Load has not been
+// called yet, Changed returns true.
+//
+// Changed does not read or apply the files, so it is cheap enough to be called
+// from a polling loop that skips no-op reloads.
+func (ue *UdotEnv) Changed() (bool, error) {
+	ue.mu.RLock()
+	defer ue.mu.RUnlock()
+	return ue.changed()
+}
+
+// changed implements Changed; the caller holds the lock.
+func (ue *UdotEnv) changed() (bool, error) {
+	if ue.stamps == nil {
+		return true, nil
+	}
+
+	for _, f := range ue.envFiles() {
+		if _, ok := ue.stamps[f.path]; !ok && !isRemote(f.path) {
+			return true, nil
+		}
+	}
+
+	for path, old := range ue.stamps {
+		cur, err := stampFile(path)
+		if err != nil {
+			return false, err
+		}
+		if cur.exists != old.exists || cur.size != old.size || !cur.modTime.Equal(old.modTime) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,100 @@
+package udotenv
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanged_BeforeLoad(t *testing.T) {
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestChanged_AfterLoad(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"CHANGED_KEY": "one"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("CHANGED_KEY")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	_ = godotenv.Write(map[string]string{"CHANGED_KEY": "three"}, ".test.env")
+	changed, err = udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestChanged_RewrittenDuringLoad(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"CHANGED_KEY": "one"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("CHANGED_KEY")
+
+	// the source is fetched once the file is read, standing for a write
+	// racing with the load
+	rewrite := SourceFunc(func(context.Context) (map[string]string, error) {
+		_ = godotenv.Write(map[string]string{"CHANGED_KEY": "three"}, ".test.env")
+		return nil, nil
+	})
+	udotEnv := &UdotEnv{Config: &Config{Sources: []Source{rewrite}}, EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "one", os.Getenv("CHANGED_KEY"))
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestChanged_ModTime(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"CHANGED_KEY": "one"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("CHANGED_KEY")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+
+	future := time.Now().Add(time.Hour)
+	_ = os.Chtimes(".test.env", future, future)
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestChanged_Deleted(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"CHANGED_KEY": "one"}, ".test.env")
+	defer os.Unsetenv("CHANGED_KEY")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+	os.Remove(".test.env")
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestChanged_NewFile(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"CHANGED_KEY": "one"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("CHANGED_KEY")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+	udotEnv.EnvParam = append(udotEnv.EnvParam, ".test2.env")
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
@@ -0,0 +1,38 @@
+package udotenv
+
+// ExampleReport is the result of CompareExample.
+type ExampleReport struct {
+	// Missing holds the keys of the example that the env file lacks.
+	Missing []string
+	// Extra holds the keys of the env file that the example lacks.
+	Extra []string
+}
+
+// CompareExample compares the keys of the env file at path with the ones of
+// the example file, conventionally `.env.example`, which documents the
+// variables an application expects. The keys of the report are sorted.
+// Values are not compared: an empty value still counts as present.
+func CompareExample(path, example string) (ExampleReport, error) {
+	ue := &UdotEnv{}
+	vars, err := ue.readFile(path)
+	if err != nil {
+		return ExampleReport{}, err
+	}
+	expected, err := ue.readFile(example)
+	if err != nil {
+		return ExampleReport{}, err
+	}
+
+	var r ExampleReport
+	for _, k := range sortedKeys(expected) {
+		if _, ok := vars[k]; !ok {
+			r.Missing = append(r.Missing, k)
+		}
+	}
+	for _, k := range sortedKeys(vars) {
+		if _, ok := expected[k]; !ok {
+			r.Extra = append(r.Extra, k)
+		}
+	}
+	return r, nil
+}
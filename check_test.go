@@ -0,0 +1,29 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareExample(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	example := filepath.Join(dir, ".env.example")
+	_ = os.WriteFile(path, []byte("DB_HOST=db\nDB_PASSWORD=\nDEBUG=1\n"), 0o644)
+	_ = os.WriteFile(example, []byte("# required\nDB_HOST=\nDB_PASSWORD=\nDB_PORT=5432\nAPI_KEY=\n"), 0o644)
+
+	r, err := CompareExample(path, example)
+	assert.NoError(t, err)
+	assert.Equal(t, ExampleReport{Missing: []string{"API_KEY", "DB_PORT"}, Extra: []string{"DEBUG"}}, r)
+
+	r, err = CompareExample(example, example)
+	assert.NoError(t, err)
+	assert.Equal(t, ExampleReport{}, r)
+
+	missing := filepath.Join(dir, "missing")
+	_, err = CompareExample(path, missing)
+	assert.ErrorContains(t, err, "error loading file '"+missing+"'")
+}
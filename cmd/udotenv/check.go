@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// checkCommand implements `udotenv check -example file`, reporting the keys
+// of the example missing from the env file. It fails if there are any, or
+// with -extra if the env file has keys the example lacks.
+func checkCommand(args []string, stdout, stderr io.Writer) int {
+	fs, path := fileFlags("check", "-example file [-extra]",
+		"Reports the keys of the example file missing from the env file.", stderr)
+	example := fs.String("example", ".env.example", "the example `file` listing the expected keys")
+	extra := fs.Bool("extra", false, "also report the keys missing from the example")
+	if code := parseArgs(fs, args, 0, stderr); code != -1 {
+		return code
+	}
+
+	r, err := udotenv.CompareExample(*path, *example)
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	for _, k := range r.Missing {
+		fmt.Fprintf(stdout, "missing: %s\n", k)
+		code = 1
+	}
+	if *extra {
+		for _, k := range r.Extra {
+			fmt.Fprintf(stdout, "extra: %s\n", k)
+			code = 1
+		}
+	}
+	return code
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCommand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	example := filepath.Join(dir, ".env.example")
+	_ = os.WriteFile(path, []byte("APP_NAME=api\nAPP_DEBUG=1\n"), 0o644)
+	_ = os.WriteFile(example, []byte("APP_NAME=\nAPP_DEBUG=\n"), 0o644)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 0, dispatch([]string{"check", "-f", path, "-example", example}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+
+	_ = os.WriteFile(example, []byte("APP_NAME=\nAPP_TOKEN=\n"), 0o644)
+	assert.Equal(t, 1, dispatch([]string{"check", "-f", path, "-example", example}, &stdout, &stderr))
+	assert.Equal(t, "missing: APP_TOKEN\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, dispatch([]string{"check", "-f", path, "-example", example, "-extra"}, &stdout, &stderr))
+	assert.Equal(t, "missing: APP_TOKEN\nextra: APP_DEBUG\n", stdout.String())
+
+	_ = os.WriteFile(example, []byte("APP_NAME=\n"), 0o644)
+	stdout.Reset()
+	assert.Equal(t, 0, dispatch([]string{"check", "-f", path, "-example", example}, &stdout, &stderr))
+	assert.Equal(t, 1, dispatch([]string{"check", "-f", path, "-example", example, "-extra"}, &stdout, &stderr))
+	assert.Equal(t, "extra: APP_DEBUG\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	missing := filepath.Join(dir, "missing")
+	assert.Equal(t, 1, dispatch([]string{"check", "-f", path, "-example", missing}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "udotenv: error loading file '"+missing+"'")
+}
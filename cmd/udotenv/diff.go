@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// diffCommand implements `udotenv diff fileA fileB`, printing the keys added,
+// removed and changed from fileA to fileB. The values of the keys that look
+// secret, such as API_TOKEN, are masked unless -values is passed. Like diff(1), it exits with 1 if the files differ and
+// with 2 if one of them cannot be read.
+func diffCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("udotenv diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: udotenv diff [-values] fileA fileB")
+		fmt.Fprintln(stderr, "\nPrints the keys added (+), removed (-) and changed (~) from fileA to fileB.")
+		fs.PrintDefaults()
+	}
+	show := fs.Bool("values", false, "print the values of secret keys instead of masking them")
+	if code := parseArgs(fs, args, 2, stderr); code != -1 {
+		return code
+	}
+
+	files := make([]map[string]string, 2)
+	for i, path := range fs.Args() {
+		doc, err := open(path, false)
+		if err != nil {
+			fmt.Fprintf(stderr, "udotenv: %v\n", err)
+			return 2
+		}
+		files[i] = doc.Vars()
+	}
+
+	ue := &udotenv.UdotEnv{Config: udotenv.GetDefaultConfig()}
+	mask := func(k, v string) string {
+		if *show {
+			return v
+		}
+		return ue.MaskValue(k, v)
+	}
+
+	changes := udotenv.Diff(files[0], files[1])
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		c := changes[k]
+		switch c.Kind {
+		case udotenv.Added:
+			fmt.Fprintf(stdout, "+ %s=%s\n", k, mask(k, c.New))
+		case udotenv.Removed:
+			fmt.Fprintf(stdout, "- %s=%s\n", k, mask(k, c.Old))
+		case udotenv.Modified:
+			fmt.Fprintf(stdout, "~ %s: %s -> %s\n", k, mask(k, c.Old), mask(k, c.New))
+		}
+	}
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
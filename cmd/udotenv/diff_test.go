@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	staging := filepath.Join(dir, ".env.staging")
+	prod := filepath.Join(dir, ".env.prod")
+	_ = os.WriteFile(staging, []byte("DB_HOST=staging\nDB_PASSWORD=one\nDEBUG=1\n"), 0o644)
+	_ = os.WriteFile(prod, []byte("DB_HOST=prod\nDB_PASSWORD=three\nREPLICAS=3\n"), 0o644)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 1, dispatch([]string{"diff", staging, prod}, &stdout, &stderr))
+	assert.Equal(t, "~ DB_HOST: staging -> prod\n~ DB_PASSWORD: [masked, 3 bytes] -> [masked, 5 bytes]\n- DEBUG=1\n+ REPLICAS=3\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, dispatch([]string{"diff", "-values", staging, prod}, &stdout, &stderr))
+	assert.Equal(t, "~ DB_HOST: staging -> prod\n~ DB_PASSWORD: one -> three\n- DEBUG=1\n+ REPLICAS=3\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, dispatch([]string{"diff", prod, prod}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+
+	assert.Equal(t, 2, dispatch([]string{"diff", prod}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: udotenv diff [-values] fileA fileB")
+
+	stderr.Reset()
+	typo := filepath.Join(dir, ".env.prdo")
+	assert.Equal(t, 2, dispatch([]string{"diff", prod, typo}, &stdout, &stderr))
+	assert.Equal(t, "udotenv: error opening file '"+typo+"': no such file or directory\n", stderr.String())
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// fileFlags returns the flag set of a command working on one env file, with
+// the -f and -file flags naming the file, .env by default.
+func fileFlags(name, args, summary string, stderr io.Writer) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("udotenv "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: udotenv %s [-f file] %s\n\n%s\n", name, args, summary)
+		fs.PrintDefaults()
+	}
+
+	path := new(string)
+	fs.StringVar(path, "f", ".env", "the env `file`")
+	fs.StringVar(path, "file", ".env", "the env `file`")
+	return fs, path
+}
+
+// parseArgs parses args with fs and checks that n positional arguments
+// remain. It returns -1 if so, and the exit code of the command otherwise.
+func parseArgs(fs *flag.FlagSet, args []string, n int, stderr io.Writer) int {
+	if err := fs.Parse(args); err != nil {
+		return flagError(fs, err, stderr)
+	}
+	if fs.NArg() != n {
+		fs.Usage()
+		return 2
+	}
+	return -1
+}
+
+// getCommand implements `udotenv get KEY`, printing the value of KEY in the
+// env file.
+func getCommand(args []string, stdout, stderr io.Writer) int {
+	fs, path := fileFlags("get", "KEY", "Prints the value of KEY in the env file.", stderr)
+	if code := parseArgs(fs, args, 1, stderr); code != -1 {
+		return code
+	}
+
+	doc, err := open(*path, false)
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+	v, ok := doc.Get(fs.Arg(0))
+	if !ok {
+		fmt.Fprintf(stderr, "udotenv: %s is not set in %s\n", fs.Arg(0), *path)
+		return 1
+	}
+	fmt.Fprintln(stdout, v)
+	return 0
+}
+
+// setCommand implements `udotenv set KEY VALUE`, setting KEY in the env file,
+// which is created if needed.
+func setCommand(args []string, stdout, stderr io.Writer) int {
+	fs, path := fileFlags("set", "KEY VALUE", "Sets KEY to VALUE in the env file, creating it if needed.", stderr)
+	if code := parseArgs(fs, args, 2, stderr); code != -1 {
+		return code
+	}
+
+	return edit(*path, true, stderr, func(doc *udotenv.Document) (bool, error) {
+		return true, doc.Set(fs.Arg(0), fs.Arg(1))
+	})
+}
+
+// unsetCommand implements `udotenv unset KEY`, removing KEY from the env
+// file, which must exist. The file is left untouched if KEY is not set.
+func unsetCommand(args []string, stdout, stderr io.Writer) int {
+	fs, path := fileFlags("unset", "KEY", "Removes KEY from the env file.", stderr)
+	if code := parseArgs(fs, args, 1, stderr); code != -1 {
+		return code
+	}
+
+	return edit(*path, false, stderr, func(doc *udotenv.Document) (bool, error) {
+		return doc.Unset(fs.Arg(0)), nil
+	})
+}
+
+// open opens the env file at path, which must exist unless create is set.
+// udotenv.Open treats a missing file as an empty one, which only suits the
+// commands creating it.
+func open(path string, create bool) (*udotenv.Document, error) {
+	if !create {
+		if _, err := os.Stat(path); err != nil {
+			// the path error would repeat the path
+			return nil, fmt.Errorf("error opening file '%s': %w", path, errors.Unwrap(err))
+		}
+	}
+	return udotenv.Open(path)
+}
+
+// edit applies change to the env file at path, created if create is set,
+// and saves it if change reports that it changed.
+func edit(path string, create bool, stderr io.Writer, change func(doc *udotenv.Document) (bool, error)) int {
+	doc, err := open(path, create)
+	changed := false
+	if err == nil {
+		changed, err = change(doc)
+	}
+	if err == nil && changed {
+		err = doc.Save()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// listCommand implements `udotenv list [--json] [--values]`, printing the
+// variables of the env file. The values of the keys that look secret, such
+// as API_TOKEN, are masked unless -values is passed.
+func listCommand(args []string, stdout, stderr io.Writer) int {
+	fs, path := fileFlags("list", "[-json] [-values]", "Prints the variables of the env file, sorted by key.", stderr)
+	asJSON := fs.Bool("json", false, "print a JSON object")
+	show := fs.Bool("values", false, "print the values of secret keys instead of masking them")
+	if code := parseArgs(fs, args, 0, stderr); code != -1 {
+		return code
+	}
+
+	doc, err := open(*path, false)
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+
+	vars := doc.Vars()
+	if !*show {
+		ue := &udotenv.UdotEnv{Config: udotenv.GetDefaultConfig()}
+		for k, v := range vars {
+			vars[k] = ue.MaskValue(k, v)
+		}
+	}
+
+	var out []byte
+	if *asJSON {
+		out, err = json.MarshalIndent(vars, "", "  ")
+		out = append(out, '\n')
+	} else {
+		out, err = udotenv.Marshal(vars)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+	stdout.Write(out)
+	return 0
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("# app\nAPP_NAME=api\nAPP_DEBUG=true\n"), 0o644)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 0, dispatch([]string{"get", "-f", path, "APP_NAME"}, &stdout, &stderr))
+	assert.Equal(t, "api\n", stdout.String())
+
+	assert.Equal(t, 0, dispatch([]string{"set", "-file", path, "APP_URL", "http://x y"}, &stdout, &stderr))
+	assert.Equal(t, 0, dispatch([]string{"set", "-f", path, "APP_NAME", "web"}, &stdout, &stderr))
+	assert.Equal(t, 0, dispatch([]string{"unset", "-f", path, "APP_DEBUG"}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	src, _ := os.ReadFile(path)
+	assert.Equal(t, "# app\nAPP_NAME=web\nAPP_URL=\"http://x y\"\n", string(src))
+
+	stdout.Reset()
+	assert.Equal(t, 0, dispatch([]string{"list", "-f", path}, &stdout, &stderr))
+	assert.Equal(t, "APP_NAME=web\nAPP_URL=\"http://x y\"\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, dispatch([]string{"list", "-f", path, "-json"}, &stdout, &stderr))
+	assert.Equal(t, "{\n  \"APP_NAME\": \"web\",\n  \"APP_URL\": \"http://x y\"\n}\n", stdout.String())
+}
+
+func TestListCommand_Masked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("API_TOKEN=abc123\nAPP_NAME=api\n"), 0o644)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 0, dispatch([]string{"list", "-f", path}, &stdout, &stderr))
+	assert.Equal(t, "API_TOKEN=\"[masked, 6 bytes]\"\nAPP_NAME=api\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, dispatch([]string{"list", "-f", path, "-values"}, &stdout, &stderr))
+	assert.Equal(t, "API_TOKEN=abc123\nAPP_NAME=api\n", stdout.String())
+}
+
+func TestEditCommands_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	var stdout, stderr bytes.Buffer
+
+	for _, cmd := range []string{"get", "unset"} {
+		stderr.Reset()
+		assert.Equal(t, 1, dispatch([]string{cmd, "-f", path, "MISSING"}, &stdout, &stderr))
+		assert.Equal(t, "udotenv: error opening file '"+path+"': no such file or directory\n", stderr.String())
+	}
+	stderr.Reset()
+	assert.Equal(t, 1, dispatch([]string{"list", "-f", path}, &stdout, &stderr))
+	assert.Equal(t, "udotenv: error opening file '"+path+"': no such file or directory\n", stderr.String())
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	_ = os.WriteFile(path, []byte("APP_NAME=api\n"), 0o644)
+	stderr.Reset()
+	assert.Equal(t, 1, dispatch([]string{"get", "-f", path, "MISSING"}, &stdout, &stderr))
+	assert.Equal(t, "udotenv: MISSING is not set in "+path+"\n", stderr.String())
+	future := time.Now().Add(time.Hour)
+	_ = os.Chtimes(path, future, future)
+	assert.Equal(t, 0, dispatch([]string{"unset", "-f", path, "MISSING"}, &stdout, &stderr))
+	info, _ := os.Stat(path)
+	assert.True(t, info.ModTime().Equal(future))
+	os.Remove(path)
+
+	stderr.Reset()
+	assert.Equal(t, 1, dispatch([]string{"set", "-f", path, "BAD KEY", "v"}, &stdout, &stderr))
+	assert.Equal(t, "udotenv: invalid variable name \"BAD KEY\"\n", stderr.String())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	stderr.Reset()
+	assert.Equal(t, 2, dispatch([]string{"set", "-f", path, "ONLY_KEY"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: udotenv set [-f file] KEY VALUE")
+
+	_ = os.WriteFile(path, []byte("BROKEN\n"), 0o644)
+	stderr.Reset()
+	assert.Equal(t, 1, dispatch([]string{"list", "-f", path}, &stdout, &stderr))
+	assert.Equal(t, "udotenv: error opening file '"+path+"': line 1, column 1: missing '=' after \"BROKEN\"\n", stderr.String())
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// lintCommand implements `udotenv lint [file]...`, printing the issues found
+// in the env files, .env by default. It exits with 1 if there are any.
+func lintCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("udotenv lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: udotenv lint [file]...")
+		fmt.Fprintln(stderr, "\nReports duplicate keys, invalid names, suspicious quoting and whitespace issues.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return flagError(fs, err, stderr)
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{".env"}
+	}
+
+	code := 0
+	for _, path := range paths {
+		issues, err := udotenv.Lint(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "udotenv: %v\n", err)
+			code = 1
+			continue
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%s: %s\n", path, issue)
+			code = 1
+		}
+	}
+	return code
+}
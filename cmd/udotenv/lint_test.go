@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintCommand(t *testing.T) {
+	dir := t.TempDir()
+	clean := filepath.Join(dir, ".env")
+	dirty := filepath.Join(dir, ".env.local")
+	_ = os.WriteFile(clean, []byte("APP_NAME=api\n"), 0o644)
+	_ = os.WriteFile(dirty, []byte("APP_NAME=api\nAPP_NAME=web\n"), 0o644)
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 0, dispatch([]string{"lint", clean}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+
+	assert.Equal(t, 1, dispatch([]string{"lint", clean, dirty}, &stdout, &stderr))
+	assert.Equal(t, dirty+": line 2: APP_NAME is already defined on line 1 (duplicate-key)\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	missing := filepath.Join(dir, "missing")
+	assert.Equal(t, 1, dispatch([]string{"lint", missing}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "udotenv: open "+missing)
+}
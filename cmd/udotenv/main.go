@@ -0,0 +1,84 @@
+// Command udotenv loads env files and works with them from the shell.
+//
+// Usage:
+//
+//	udotenv <command> [arguments]
+//
+// The commands are:
+//
+//	run    run a command with the variables of the env files
+//	get    print the value of a key of an env file
+//	set    set a key in an env file
+//	unset  remove a key from an env file
+//	list   print the variables of an env file
+//	check  compare an env file with its example
+//	diff   compare two env files
+//	lint   report likely mistakes in env files
+//
+// Run `udotenv <command> -h` for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// command is a subcommand of udotenv. It returns the exit code of the
+// process.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "run", summary: "run a command with the variables of the env files", run: runCommand},
+		{name: "get", summary: "print the value of a key of an env file", run: getCommand},
+		{name: "set", summary: "set a key in an env file", run: setCommand},
+		{name: "unset", summary: "remove a key from an env file", run: unsetCommand},
+		{name: "list", summary: "print the variables of an env file", run: listCommand},
+		{name: "check", summary: "compare an env file with its example", run: checkCommand},
+		{name: "diff", summary: "compare two env files", run: diffCommand},
+		{name: "lint", summary: "report likely mistakes in env files", run: lintCommand},
+	}
+}
+
+func main() {
+	os.Exit(dispatch(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// dispatch runs the subcommand named by the first of args.
+func dispatch(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == args[0] })
+	if i == -1 {
+		fmt.Fprintf(stderr, "udotenv: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return commands[i].run(args[1:], stdout, stderr)
+}
+
+// usage writes the list of the commands to w.
+func usage(w io.Writer) {
+	var b strings.Builder
+	b.WriteString("Usage: udotenv <command> [arguments]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "  %-8s %s\n", c.name, c.summary)
+	}
+	b.WriteString("\nRun 'udotenv <command> -h' for the flags of a command.\n")
+	io.WriteString(w, b.String())
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatch(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 2, dispatch(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: udotenv <command> [arguments]")
+	assert.Contains(t, stderr.String(), "  run      run a command with the variables of the env files\n")
+
+	stderr.Reset()
+	assert.Equal(t, 0, dispatch([]string{"--help"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: udotenv <command> [arguments]")
+
+	stderr.Reset()
+	assert.Equal(t, 2, dispatch([]string{"nope"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "udotenv: unknown command \"nope\"\n")
+	assert.Empty(t, stdout.String())
+}
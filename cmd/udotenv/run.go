@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// exit codes of run when the command cannot be started, as in POSIX shells
+const (
+	exitCannotExecute = 126
+	exitNotFound      = 127
+)
+
+// runCommand implements `udotenv run [-e file]... [-o] [--] cmd args...`: it
+// loads the env files, then runs cmd with the resulting environment. The
+// signals received meanwhile are forwarded to cmd, and its exit code is
+// returned. With --env-dry-run, the variables that would be set are printed
+// instead and cmd is not run.
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("udotenv run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: udotenv run [-e file]... [-o] [--env-dry-run] [--] command [arguments]")
+		fmt.Fprintln(stderr, "\nLoads the env files, .env by default, and runs the command with them.")
+		fs.PrintDefaults()
+	}
+	ue, err := parseFlags(fs, separateCommand(args, udotenv.GetDefaultConfig().EnvFlags))
+	if err != nil {
+		return flagError(fs, err, stderr)
+	}
+	if ue.DryRunParam {
+		return plan(ue, stdout, stderr)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := ue.Load(); err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// registered before the start, so that a signal received meanwhile is
+	// forwarded instead of terminating udotenv and orphaning the command
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, forwardedSignals...)
+	if err := cmd.Start(); err != nil {
+		signal.Stop(sigs)
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return exitNotFound
+		}
+		return exitCannotExecute
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				_ = cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err = cmd.Wait()
+	signal.Stop(sigs)
+	close(done)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr.ProcessState)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// plan prints the variables that loading the env files of ue would set,
+// without running the command.
+func plan(ue *udotenv.UdotEnv, stdout, stderr io.Writer) int {
+	keys, err := ue.Plan()
+	for _, k := range keys {
+		fmt.Fprintln(stdout, k)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// separateCommand inserts `--` before the command in args, the first
+// argument that is neither a flag nor the file passed to one of envFlags, so
+// that the arguments of the command are left alone by the flags of udotenv
+// even if they look the same, as in `udotenv run ls -e`. As with the
+// flags, an env flag takes the next argument as its file unless it starts
+// with a dash.
+func separateCommand(args []string, envFlags []string) []string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			separated := append(slices.Clone(args[:i]), "--")
+			return append(separated, args[i:]...)
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		// an env flag may end a group of single-letter flags, as in -oe
+		takesFile := slices.Contains(envFlags, name) ||
+			(!strings.HasPrefix(arg, "--") && len(name) > 1 && slices.Contains(envFlags, name[len(name)-1:]))
+		if takesFile && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+		}
+	}
+	return args
+}
+
+// parseFlags registers the env and overload flags on fs and parses args.
+// When no env file is passed, the default one is used if it exists.
+func parseFlags(fs *flag.FlagSet, args []string) (*udotenv.UdotEnv, error) {
+	ue, err := udotenv.NewWithOptions(udotenv.WithFlagSet(fs), udotenv.WithArgs(args))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ue.Files()) == 0 {
+		if _, err := os.Stat(ue.Config.DefaultEnvPath); err == nil {
+			ue.EnvParam.Set(ue.Config.DefaultEnvPath)
+		}
+	}
+	return ue, nil
+}
+
+// flagError returns the exit code for an error returned by parseFlags: 0 if
+// the help was requested, 2 otherwise. The flag set reports its own errors,
+// the others are printed to stderr.
+func flagError(fs *flag.FlagSet, err error, stderr io.Writer) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if !fs.Parsed() {
+		fmt.Fprintf(stderr, "udotenv: %v\n", err)
+	}
+	return 2
+}
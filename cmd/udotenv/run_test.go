@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.env")
+	_ = os.WriteFile(path, []byte("RUN_GREETING=hello\nRUN_KEPT=file\n"), 0o644)
+	t.Setenv("RUN_KEPT", "env")
+	defer os.Unsetenv("RUN_GREETING")
+
+	var stdout, stderr bytes.Buffer
+	code := dispatch([]string{"run", "-e", path, "--", "sh", "-c", `echo "$RUN_GREETING $RUN_KEPT"; exit 3`}, &stdout, &stderr)
+	assert.Equal(t, 3, code)
+	assert.Equal(t, "hello env\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	stdout.Reset()
+	code = dispatch([]string{"run", "-e", path, "-o", "sh", "-c", `echo "$RUN_KEPT"`}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "file\n", stdout.String())
+}
+
+func TestRun_CommandFlags(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.env")
+	_ = os.WriteFile(path, []byte("RUN_FLAGS=file\n"), 0o644)
+	defer os.Unsetenv("RUN_FLAGS")
+	script := `printf '%s ' "$RUN_FLAGS" "$@"`
+
+	// the arguments of the command are not taken for the flags of udotenv
+	var stdout, stderr bytes.Buffer
+	code := dispatch([]string{"run", "-o", "-e", path, "sh", "-c", script, "sh", "-e", "-o", "-eo"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "file -e -o -eo ", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	stdout.Reset()
+	code = dispatch([]string{"run", "-oe", path, "sh", "-c", script, "sh", "-o"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "file -o ", stdout.String())
+}
+
+func TestSeparateCommand(t *testing.T) {
+	envFlags := []string{"envs", "e"}
+	assert.Equal(t, []string{"--", "echo", "-e"}, separateCommand([]string{"echo", "-e"}, envFlags))
+	assert.Equal(t, []string{"-e", "a.env", "-o", "--", "ls", "-o"},
+		separateCommand([]string{"-e", "a.env", "-o", "ls", "-o"}, envFlags))
+	assert.Equal(t, []string{"-e=a.env", "--", "ls"}, separateCommand([]string{"-e=a.env", "ls"}, envFlags))
+	assert.Equal(t, []string{"-e", "--", "ls"}, separateCommand([]string{"-e", "--", "ls"}, envFlags))
+	assert.Equal(t, []string{"-e", "-o"}, separateCommand([]string{"-e", "-o"}, envFlags))
+}
+
+func TestRun_Signaled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 143, dispatch([]string{"run", "sh", "-c", "kill -TERM $$"}, &stdout, &stderr))
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 127, dispatch([]string{"run", "udotenv-missing-command"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "udotenv: exec: \"udotenv-missing-command\": executable file not found")
+
+	stderr.Reset()
+	assert.Equal(t, 2, dispatch([]string{"run"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Usage: udotenv run [-e file]... [-o] [--env-dry-run] [--] command [arguments]")
+
+	stderr.Reset()
+	assert.Equal(t, 0, dispatch([]string{"run", "-h"}, &stdout, &stderr))
+
+	stderr.Reset()
+	missing := filepath.Join(t.TempDir(), "missing.env")
+	assert.Equal(t, 1, dispatch([]string{"run", "-e", missing, "true"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "udotenv: error loading file '"+missing+"'")
+
+	stderr.Reset()
+	assert.Equal(t, 2, dispatch([]string{"run", "-o", "-eo", "true"}, &stdout, &stderr))
+	assert.Equal(t, "udotenv: only one flag per param must be passed\n", stderr.String())
+}
+
+func TestRun_DryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.env")
+	_ = os.WriteFile(path, []byte("DRY_NEW=1\nDRY_KEPT=1\n"), 0o644)
+	t.Setenv("DRY_KEPT", "env")
+
+	var stdout, stderr bytes.Buffer
+	code := dispatch([]string{"run", "-e", path, "-o", "--env-dry-run", "--", "false"}, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "DRY_KEPT from "+path+" (overrides the environment)\nDRY_NEW from "+path+"\n", stdout.String())
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "env", os.Getenv("DRY_KEPT"))
+	_, ok := os.LookupEnv("DRY_NEW")
+	assert.False(t, ok)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// forwardedSignals are the signals passed on to the command run by `run`.
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// exitCode returns the exit code of a process that failed.
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,115 @@
+package udotenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const defaultCommandTimeout = 10 * time.Second
+
+// substituteCommands replaces the `$(command)` substitutions in the unquoted
+// and double-quoted values of doc with the output of the commands. Escaped
+// substitutions (`\$(command)`) are kept.
+func (ue *UdotEnv) substituteCommands(doc *document) error {
+	for i, st := range doc.statements {
+		if st.quote == singleQuote || !strings.Contains(st.value, "$(") {
+			continue
+		}
+
+		value, err := ue.substitute(st.value)
+		if err != nil {
+			return fmt.Errorf("line %d: command substitution in value of %s: %w", st.line, st.key, err)
+		}
+		doc.statements[i].value = value
+	}
+	return nil
+}
+
+// substitute replaces the command substitutions in s.
+func (ue *UdotEnv) substitute(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			b.WriteString(s[i : i+2])
+			i++
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "$(") {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		end := matchingParen(s, i+1)
+		if end == -1 {
+			return "", fmt.Errorf("unterminated command %q", s[i:])
+		}
+		out, err := ue.runCommand(s[i+2 : end])
+		if err != nil {
+			return "", err
+		}
+		// the output is literal, it must not be expanded or rendered
+		out = strings.ReplaceAll(out, "$", `\$`)
+		if ue.Config != nil && ue.Config.Templates {
+			out = strings.ReplaceAll(out, "{{", `{{"{{"}}`)
+		}
+		b.WriteString(out)
+		i = end
+	}
+	return b.String(), nil
+}
+
+// runCommand runs command with the shell and returns its output without the
+// trailing newlines, like a shell substitution.
+func (ue *UdotEnv) runCommand(command string) (string, error) {
+	timeout := defaultCommandTimeout
+	if ue.Config.CommandTimeout > 0 {
+		timeout = ue.Config.CommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ue.loadContext(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// do not wait for the children of the shell holding its output open
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%q timed out after %s", command, timeout)
+		}
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return "", fmt.Errorf("%q: %w: %s", command, err, msg)
+		}
+		return "", fmt.Errorf("%q: %w", command, err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// matchingParen returns the index of the parenthesis closing the one at
+// index open of s, or -1.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,66 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_CommandSubstitution(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte(
+		"CMD_TOKEN=$(echo secret)\n"+
+			"CMD_QUOTED=\"id-$(printf '%s' \\\"$(echo nested)\\\")\"\n"+
+			"CMD_DOLLAR=$(echo 'pa$$word')\n"+
+			"CMD_LITERAL='$(echo literal)'\n"+
+			"CMD_ESCAPED=\"\\$(echo escaped)\"\n"), 0o644)
+	for _, k := range []string{"CMD_TOKEN", "CMD_QUOTED", "CMD_DOLLAR", "CMD_LITERAL", "CMD_ESCAPED"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{AllowCommandSubstitution: true}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "secret", os.Getenv("CMD_TOKEN"))
+	assert.Equal(t, "id-nested", os.Getenv("CMD_QUOTED"))
+	assert.Equal(t, "pa$$word", os.Getenv("CMD_DOLLAR"))
+	assert.Equal(t, "$(echo literal)", os.Getenv("CMD_LITERAL"))
+	assert.Equal(t, "$(echo escaped)", os.Getenv("CMD_ESCAPED"))
+}
+
+func TestLoad_CommandSubstitutionDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("CMD_DISABLED='$(echo secret)'\n"), 0o644)
+	defer os.Unsetenv("CMD_DISABLED")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "$(echo secret)", os.Getenv("CMD_DISABLED"))
+}
+
+func TestLoad_CommandSubstitutionErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	udotEnv := &UdotEnv{
+		Config:   &Config{AllowCommandSubstitution: true, CommandTimeout: 50 * time.Millisecond},
+		EnvParam: stringSlice{path},
+	}
+
+	_ = os.WriteFile(path, []byte("CMD_FAIL=$(echo failed >&2; exit 3)\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+path+
+		"': line 1: command substitution in value of CMD_FAIL: \"echo failed >&2; exit 3\": exit status 3: failed")
+
+	_ = os.WriteFile(path, []byte("CMD_SLOW=$(sleep 5)\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+path+
+		"': line 1: command substitution in value of CMD_SLOW: \"sleep 5\" timed out after 50ms")
+}
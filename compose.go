@@ -0,0 +1,30 @@
+package udotenv
+
+import (
+	"fmt"
+	"os"
+)
+
+// assignCompose sets the variable defined by st in vars, interpolating its
+// value like Docker Compose does unless it is single-quoted. References are
+// resolved from the environment, then from vars.
+func assignCompose(vars map[string]string, st statement) error {
+	if st.quote == singleQuote {
+		vars[st.key] = st.value
+		return nil
+	}
+
+	lookup := func(k string) (string, bool, error) {
+		if v, ok := os.LookupEnv(k); ok {
+			return v, true, nil
+		}
+		v, ok := vars[k]
+		return v, ok, nil
+	}
+	v, err := interpolate(st.value, st.key, lookup, true)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", st.line, err)
+	}
+	vars[st.key] = v
+	return nil
+}
@@ -0,0 +1,62 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_DialectCompose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte(
+		"COMPOSE_HOST=db\n"+
+			"COMPOSE_URL=postgres://${COMPOSE_HOST}:${COMPOSE_PORT:-5432}\n"+
+			"COMPOSE_ALT=${COMPOSE_HOST:+set}${COMPOSE_MISSING:+unset}\n"+
+			"COMPOSE_PRICE=\"$$5 ${COMPOSE_USER}\"\n"+
+			"COMPOSE_LITERAL='${COMPOSE_HOST}'\n"+
+			"COMPOSE_REQUIRED=${COMPOSE_HOST:?host is required}\n"), 0o644)
+	t.Setenv("COMPOSE_USER", "env")
+	for _, k := range []string{"COMPOSE_HOST", "COMPOSE_URL", "COMPOSE_ALT", "COMPOSE_PRICE", "COMPOSE_LITERAL", "COMPOSE_REQUIRED"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{Dialect: DialectCompose}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "postgres://db:5432", os.Getenv("COMPOSE_URL"))
+	assert.Equal(t, "set", os.Getenv("COMPOSE_ALT"))
+	assert.Equal(t, "$5 env", os.Getenv("COMPOSE_PRICE"))
+	assert.Equal(t, "${COMPOSE_HOST}", os.Getenv("COMPOSE_LITERAL"))
+	assert.Equal(t, "db", os.Getenv("COMPOSE_REQUIRED"))
+}
+
+func TestLoad_DialectComposeRequired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	udotEnv := &UdotEnv{Config: &Config{Dialect: DialectCompose}, EnvParam: stringSlice{path}}
+
+	_ = os.WriteFile(path, []byte("COMPOSE_A=1\nCOMPOSE_B=${COMPOSE_UNSET:?must be set}\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+path+
+		"': line 2: required variable COMPOSE_UNSET is missing a value: must be set")
+
+	_ = os.WriteFile(path, []byte("COMPOSE_EMPTY=\nCOMPOSE_B=${COMPOSE_EMPTY?}\nCOMPOSE_C=${COMPOSE_EMPTY:?}\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+path+
+		"': line 3: required variable COMPOSE_EMPTY is missing a value")
+}
+
+func TestLoad_DialectComposeExpand(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	_ = os.WriteFile(base, []byte("COMPOSE_X_URL=${COMPOSE_X_HOST:?}/$${COMPOSE_X_PATH:+app}\n"), 0o644)
+	_ = os.WriteFile(local, []byte("COMPOSE_X_HOST=local\n"), 0o644)
+	defer os.Unsetenv("COMPOSE_X_URL")
+	defer os.Unsetenv("COMPOSE_X_HOST")
+
+	udotEnv := &UdotEnv{Config: &Config{Dialect: DialectCompose, Expand: true}, EnvParam: stringSlice{base, local}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "local/${COMPOSE_X_PATH:+app}", os.Getenv("COMPOSE_X_URL"))
+
+	udotEnv.Config.Dialect = DialectGodotenv
+	assert.EqualError(t, udotEnv.Load(), "invalid reference ${COMPOSE_X_HOST:?} in value of COMPOSE_X_URL")
+}
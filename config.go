@@ -0,0 +1,74 @@
+package udotenv
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ConfigError reports a Config that cannot be used to register the flags,
+// such as a flag name shared by the env and overload flags. It matches
+// ErrInvalidConfig with errors.Is.
+type ConfigError struct {
+	Field string // field of the Config at fault, empty for a nil config
+	Flag  string // flag name at fault, if any
+	Msg   string
+}
+
+// Is makes a *ConfigError match ErrInvalidConfig.
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+func (e *ConfigError) Error() string {
+	if e.Field == "" {
+		return "invalid config: " + e.Msg
+	}
+	return fmt.Sprintf("invalid config: %s: %s", e.Field, e.Msg)
+}
+
+// Validate checks the flag names of the config: they must not be empty, start
+// with `-` or contain `=`, and a name may only be used once across the
+// EnvFlags, OverloadFlags and DryRunFlags. The first problem found is
+// returned as a *ConfigError.
+func (c *Config) Validate() error {
+	return c.validate(nil)
+}
+
+// validate checks the config like Validate and, if fs is not nil, that none
+// of its flag names is already defined on fs.
+func (c *Config) validate(fs *flag.FlagSet) error {
+	if c == nil {
+		return &ConfigError{Msg: "config must not be nil"}
+	}
+
+	fields := make(map[string]string)
+	for _, list := range []struct {
+		field string
+		names []string
+	}{
+		{"EnvFlags", c.EnvFlags},
+		{"OverloadFlags", c.OverloadFlags},
+		{"DryRunFlags", c.DryRunFlags},
+	} {
+		for _, name := range list.names {
+			switch {
+			case name == "":
+				return &ConfigError{Field: list.field, Msg: "empty flag name"}
+			case strings.HasPrefix(name, "-") || strings.Contains(name, "="):
+				return &ConfigError{Field: list.field, Flag: name, Msg: fmt.Sprintf("invalid flag name %q", name)}
+			}
+			if field, ok := fields[name]; ok {
+				if field == list.field {
+					return &ConfigError{Field: list.field, Flag: name, Msg: fmt.Sprintf("flag %q is listed twice", name)}
+				}
+				return &ConfigError{Field: list.field, Flag: name, Msg: fmt.Sprintf("flag %q is also in %s", name, field)}
+			}
+			fields[name] = list.field
+			if fs != nil && fs.Lookup(name) != nil {
+				return &ConfigError{Field: list.field, Flag: name, Msg: fmt.Sprintf("flag %q is already defined on the flag set", name)}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package udotenv
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultConfig().Validate())
+	assert.NoError(t, (&Config{}).Validate())
+
+	var nilConfig *Config
+	assert.EqualError(t, nilConfig.Validate(), "invalid config: config must not be nil")
+
+	for config, msg := range map[*Config]string{
+		{EnvFlags: []string{"envs", ""}}:                        "invalid config: EnvFlags: empty flag name",
+		{OverloadFlags: []string{"-o"}}:                         `invalid config: OverloadFlags: invalid flag name "-o"`,
+		{DryRunFlags: []string{"dry=run"}}:                      `invalid config: DryRunFlags: invalid flag name "dry=run"`,
+		{EnvFlags: []string{"e", "e"}}:                          `invalid config: EnvFlags: flag "e" is listed twice`,
+		{EnvFlags: []string{"e"}, OverloadFlags: []string{"e"}}: `invalid config: OverloadFlags: flag "e" is also in EnvFlags`,
+	} {
+		err := config.Validate()
+		assert.EqualError(t, err, msg)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	}
+}
+
+func TestNewWithFlagSet_InvalidConfig(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("o", false, "")
+
+	defer func() {
+		err, ok := recover().(*ConfigError)
+		assert.True(t, ok)
+		assert.Equal(t, &ConfigError{Field: "OverloadFlags", Flag: "o", Msg: `flag "o" is already defined on the flag set`}, err)
+	}()
+	NewWithFlagSet(fs, nil)
+}
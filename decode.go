@@ -0,0 +1,247 @@
+package udotenv
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[reflect.Type]func(string) (any, error){
+		durationType: func(s string) (any, error) {
+			return time.ParseDuration(s)
+		},
+		timeType: func(s string) (any, error) {
+			return time.Parse(time.RFC3339, s)
+		},
+		urlType: func(s string) (any, error) {
+			u, err := url.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			return *u, nil
+		},
+	}
+)
+
+// RegisterDecoder makes decode convert the values of type t for Get and Bind,
+// replacing the decoder of t if any, including the built-in ones of
+// time.Duration, time.Time and url.URL. The values returned by decode must be
+// assignable or convertible to t. Pointers to t are decoded with decode too.
+// RegisterDecoder panics if decode is nil.
+//
+//	udotenv.RegisterDecoder(reflect.TypeOf(Level(0)), func(s string) (any, error) {
+//	    return ParseLevel(s)
+//	})
+func RegisterDecoder(t reflect.Type, decode func(string) (any, error)) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	if decode == nil {
+		panic("udotenv: RegisterDecoder decode is nil")
+	}
+	decoders[t] = decode
+}
+
+// lookupDecoder returns the decoder registered for t, if any.
+func lookupDecoder(t reflect.Type) (func(string) (any, error), bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	decode, ok := decoders[t]
+	return decode, ok
+}
+
+// separators are the delimiters of the slices and maps decoded by Get and
+// Bind.
+type separators struct {
+	list     string // between the elements of a slice or the entries of a map
+	keyValue string // between the key and the value of a map entry
+}
+
+// separators returns the separators of the config, or the default ones.
+func (ue *UdotEnv) separators() separators {
+	seps := separators{list: defaultListSeparator, keyValue: defaultKeyValueSeparator}
+	if ue.Config != nil && ue.Config.ListSeparator != "" {
+		seps.list = ue.Config.ListSeparator
+	}
+	if ue.Config != nil && ue.Config.KeyValueSeparator != "" {
+		seps.keyValue = ue.Config.KeyValueSeparator
+	}
+	return seps
+}
+
+// Get returns the value of key, as loaded by the last call to Load, converted
+// to T. Besides the types of the registered decoders and the types
+// implementing encoding.TextUnmarshaler, such as net.IP or slog.Level, T may
+// be a string, a bool, an integer, a float, a slice or a map of them, or a
+// pointer to any of them. Integers and floats may separate their digits with
+// underscores, as in "1_000_000", and ByteSize decodes humanized sizes. The
+// registered decoders take precedence over UnmarshalText:
+//
+//	port, err := udotenv.Get[int](udotEnv, "PORT")
+//	hosts, err := udotenv.Get[[]string](udotEnv, "HOSTS")          // a,b,c
+//	labels, err := udotenv.Get[map[string]string](udotEnv, "LABELS") // env:prod,team:core
+//
+// The elements of slices and the entries of maps are separated by the
+// `ListSeparator` of the config, a comma by default, and the keys of the
+// entries from their values by its `KeyValueSeparator`, a colon by default.
+// A backslash escapes a separator, or itself, inside an element, and the
+// spaces around the elements, keys and values are trimmed.
+//
+// Like the getters, Get reports keys that were not loaded with ErrKeyNotFound.
+func Get[T any](ue *UdotEnv, key string) (T, error) {
+	return get(ue, key, decoder[T](ue.separators()))
+}
+
+// GetOr is like Get, but returns def when the key is missing or its value
+// cannot be converted.
+func GetOr[T any](ue *UdotEnv, key string, def T) T {
+	return getOr(ue, key, def, decoder[T](ue.separators()))
+}
+
+// decoder returns a function converting its argument to T.
+func decoder[T any](seps separators) func(string) (T, error) {
+	return func(s string) (T, error) {
+		var t T
+		err := decodeInto(reflect.ValueOf(&t).Elem(), s, seps)
+		return t, err
+	}
+}
+
+// textUnmarshaler returns v as an encoding.TextUnmarshaler if its pointer
+// implements it.
+func textUnmarshaler(v reflect.Value) (encoding.TextUnmarshaler, bool) {
+	if !v.CanAddr() || !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return nil, false
+	}
+	return v.Addr().Interface().(encoding.TextUnmarshaler), true
+}
+
+// decodeWith stores the value of s decoded by decode in v.
+func decodeWith(v reflect.Value, s string, decode func(string) (any, error)) error {
+	x, err := decode(s)
+	if err != nil {
+		return err
+	}
+
+	xv := reflect.ValueOf(x)
+	switch {
+	case !xv.IsValid():
+		v.SetZero()
+	case xv.Type().AssignableTo(v.Type()):
+		v.Set(xv)
+	case xv.Type().ConvertibleTo(v.Type()):
+		v.Set(xv.Convert(v.Type()))
+	default:
+		return fmt.Errorf("decoder of %s returned %T", v.Type(), x)
+	}
+	return nil
+}
+
+// decodeSlice splits s into elements and decodes every one of them into v.
+func decodeSlice(v reflect.Value, s string, seps separators) error {
+	parts := splitEscaped(s, seps.list)
+	slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		elem := strings.TrimSpace(unescapeSeps(part, seps.list))
+		if err := decodeInto(slice.Index(i), elem, seps); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+// decodeMap splits s into entries and decodes their keys and values into v.
+func decodeMap(v reflect.Value, s string, seps separators) error {
+	t := v.Type()
+	m := reflect.MakeMap(t)
+	for i, entry := range splitEscaped(s, seps.list) {
+		kv := splitEscaped(entry, seps.keyValue)
+		if len(kv) < 2 {
+			return fmt.Errorf("entry %d: missing %q between key and value", i, seps.keyValue)
+		}
+		// an unescaped separator in the value belongs to it
+		rawValue := strings.Join(kv[1:], seps.keyValue)
+
+		key := reflect.New(t.Key()).Elem()
+		if err := decodeInto(key, strings.TrimSpace(unescapeSeps(kv[0], seps.list, seps.keyValue)), seps); err != nil {
+			return fmt.Errorf("entry %d: key: %w", i, err)
+		}
+		value := reflect.New(t.Elem()).Elem()
+		if err := decodeInto(value, strings.TrimSpace(unescapeSeps(rawValue, seps.list, seps.keyValue)), seps); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		m.SetMapIndex(key, value)
+	}
+	v.Set(m)
+	return nil
+}
+
+// splitEscaped splits s around the occurrences of sep that are not escaped
+// by a backslash, keeping the escapes. An empty s has no parts.
+func splitEscaped(s, sep string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++ // skip the escaped byte
+		case strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeSeps removes the backslashes escaping one of seps or a backslash in s.
+// The other backslashes, as in Windows paths, are kept.
+func unescapeSeps(s string, seps ...string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if s[i+1] == '\\' {
+				b.WriteByte('\\')
+				i++
+				continue
+			}
+			if sep, ok := escapedSep(s[i+1:], seps); ok {
+				b.WriteString(sep)
+				i += len(sep)
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// escapedSep returns the separator among seps that s starts with.
+func escapedSep(s string, seps []string) (string, bool) {
+	for _, sep := range seps {
+		if strings.HasPrefix(s, sep) {
+			return sep, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,248 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "GENERIC_STRING=text\n"+
+		"GENERIC_INT8=-8\n"+
+		"GENERIC_UINT=8\n"+
+		"GENERIC_BOOL=off\n"+
+		"GENERIC_FLOAT=0.25\n"+
+		"GENERIC_DURATION=2m\n"+
+		"GENERIC_TIME=2024-05-01T10:00:00Z\n"+
+		"GENERIC_HOSTS=a, b,c\n"+
+		"GENERIC_PORTS=80,443\n"+
+		"GENERIC_URL=https://example.com/path\n")
+
+	s, err := Get[string](udotEnv, "GENERIC_STRING")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", s)
+
+	i8, err := Get[int8](udotEnv, "GENERIC_INT8")
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-8), i8)
+
+	u, err := Get[uint](udotEnv, "GENERIC_UINT")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(8), u)
+
+	b, err := Get[bool](udotEnv, "GENERIC_BOOL")
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	f, err := Get[float32](udotEnv, "GENERIC_FLOAT")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.25), f)
+
+	d, err := Get[time.Duration](udotEnv, "GENERIC_DURATION")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, d)
+
+	tm, err := Get[time.Time](udotEnv, "GENERIC_TIME")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), tm)
+
+	hosts, err := Get[[]string](udotEnv, "GENERIC_HOSTS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, hosts)
+
+	ports, err := Get[[]int](udotEnv, "GENERIC_PORTS")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{80, 443}, ports)
+
+	link, err := Get[url.URL](udotEnv, "GENERIC_URL")
+	assert.NoError(t, err)
+	assert.Equal(t, "/path", link.Path)
+
+	p, err := Get[*url.URL](udotEnv, "GENERIC_URL")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", p.Host)
+}
+
+func TestGet_Errors(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "GENERIC_BAD=abc\nGENERIC_BAD_LIST=1,x\n")
+
+	_, err := Get[int](udotEnv, "GENERIC_MISSING")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = Get[int](udotEnv, "GENERIC_BAD")
+	assert.ErrorContains(t, err, "GENERIC_BAD")
+
+	_, err = Get[[]int](udotEnv, "GENERIC_BAD_LIST")
+	assert.ErrorContains(t, err, "element 1")
+
+	_, err = Get[struct{ A int }](udotEnv, "GENERIC_BAD")
+	assert.ErrorContains(t, err, "unsupported type")
+
+	assert.Equal(t, 7, GetOr(udotEnv, "GENERIC_BAD", 7))
+	assert.Equal(t, "abc", GetOr(udotEnv, "GENERIC_BAD", "def"))
+}
+
+type testLevel int
+
+func TestRegisterDecoder(t *testing.T) {
+	levels := map[string]int{"debug": 0, "info": 1}
+	RegisterDecoder(reflect.TypeOf(testLevel(0)), func(s string) (any, error) {
+		l, ok := levels[strings.ToLower(s)]
+		if !ok {
+			return nil, errors.New("unknown level")
+		}
+		return l, nil
+	})
+	defer func() {
+		decodersMu.Lock()
+		delete(decoders, reflect.TypeOf(testLevel(0)))
+		decodersMu.Unlock()
+	}()
+
+	udotEnv := loadedUdotEnv(t, "DECODER_LEVEL=INFO\nDECODER_BAD_LEVEL=trace\n")
+
+	l, err := Get[testLevel](udotEnv, "DECODER_LEVEL")
+	assert.NoError(t, err)
+	assert.Equal(t, testLevel(1), l)
+
+	var cfg struct {
+		Level *testLevel `env:"DECODER_LEVEL"`
+	}
+	assert.NoError(t, udotEnv.Bind(&cfg))
+	assert.Equal(t, testLevel(1), *cfg.Level)
+
+	_, err = Get[testLevel](udotEnv, "DECODER_BAD_LEVEL")
+	assert.ErrorContains(t, err, "unknown level")
+
+	assert.Panics(t, func() { RegisterDecoder(reflect.TypeOf(testLevel(0)), nil) })
+}
+
+// testID is a struct decoded through UnmarshalText.
+type testID struct {
+	Kind string
+	N    int
+}
+
+func (id *testID) UnmarshalText(text []byte) error {
+	kind, n, ok := strings.Cut(string(text), "-")
+	if !ok {
+		return fmt.Errorf("invalid id %q", text)
+	}
+	id.Kind = kind
+	_, err := fmt.Sscan(n, &id.N)
+	return err
+}
+
+func TestGet_TextUnmarshaler(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "TEXT_IP=10.0.0.1\n"+
+		"TEXT_LEVEL=warn\n"+
+		"TEXT_ID=user-42\n"+
+		"TEXT_IDS=user-1,group-2\n"+
+		"TEXT_BAD_ID=42\n")
+
+	ip, err := Get[net.IP](udotEnv, "TEXT_IP")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", ip.String())
+
+	level, err := Get[slog.Level](udotEnv, "TEXT_LEVEL")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	var cfg struct {
+		ID    testID   `env:"TEXT_ID"`
+		IDs   []testID `env:"TEXT_IDS"`
+		PtrID *testID  `env:"TEXT_ID"`
+	}
+	assert.NoError(t, udotEnv.Bind(&cfg))
+	assert.Equal(t, testID{"user", 42}, cfg.ID)
+	assert.Equal(t, []testID{{"user", 1}, {"group", 2}}, cfg.IDs)
+	assert.Equal(t, &testID{"user", 42}, cfg.PtrID)
+
+	_, err = Get[testID](udotEnv, "TEXT_BAD_ID")
+	assert.ErrorContains(t, err, "invalid id")
+}
+
+func TestSplitEscaped(t *testing.T) {
+	assert.Nil(t, splitEscaped("", ","))
+	assert.Equal(t, []string{"a", "b", ""}, splitEscaped("a,b,", ","))
+	assert.Equal(t, []string{`a\,b`, "c"}, splitEscaped(`a\,b,c`, ","))
+	assert.Equal(t, []string{`a\\`, "b"}, splitEscaped(`a\\,b`, ","))
+	assert.Equal(t, []string{"a", "b"}, splitEscaped("a::b", "::"))
+	assert.Equal(t, []string{`a\`}, splitEscaped(`a\`, ","))
+}
+
+func TestUnescapeSeps(t *testing.T) {
+	assert.Equal(t, "a,b", unescapeSeps(`a\,b`, ","))
+	assert.Equal(t, `a\b`, unescapeSeps(`a\\b`, ","))
+	assert.Equal(t, `C:\dir`, unescapeSeps(`C:\dir`, ","))
+	assert.Equal(t, "k:v", unescapeSeps(`k\:v`, ",", ":"))
+}
+
+func TestGet_SlicesAndMaps(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "COLL_HOSTS=a,b,c\n"+
+		`COLL_ESCAPED=a\,b,c`+"\n"+
+		"COLL_EMPTY=\n"+
+		"COLL_LABELS=env:prod, team : core\n"+
+		`COLL_URLS=api:http://api:8080,escaped\:key:v`+"\n"+
+		"COLL_LIMITS=cpu:2,mem:4\n"+
+		"COLL_BAD_MAP=env\n")
+
+	hosts, err := Get[[]string](udotEnv, "COLL_HOSTS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, hosts)
+
+	escaped, err := Get[[]string](udotEnv, "COLL_ESCAPED")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a,b", "c"}, escaped)
+
+	empty, err := Get[[]string](udotEnv, "COLL_EMPTY")
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	labels, err := Get[map[string]string](udotEnv, "COLL_LABELS")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, labels)
+
+	urls, err := Get[map[string]string](udotEnv, "COLL_URLS")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"api": "http://api:8080", "escaped:key": "v"}, urls)
+
+	limits, err := Get[map[string]int](udotEnv, "COLL_LIMITS")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"cpu": 2, "mem": 4}, limits)
+
+	_, err = Get[map[string]string](udotEnv, "COLL_BAD_MAP")
+	assert.ErrorContains(t, err, `entry 0: missing ":"`)
+
+	_, err = Get[map[string]int](udotEnv, "COLL_LABELS")
+	assert.ErrorContains(t, err, "entry 0")
+}
+
+func TestBind_Separators(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "SEP_HOSTS=a;b\n"+
+		"SEP_LABELS=env=prod;team=core\n"+
+		"SEP_PORTS=80 443\n")
+	udotEnv.Config = &Config{ListSeparator: " "}
+
+	var cfg struct {
+		Hosts  []string          `env:"SEP_HOSTS" envSeparator:";"`
+		Labels map[string]string `env:"SEP_LABELS" envSeparator:";" envKeyValSeparator:"="`
+		Ports  []uint16          `env:"SEP_PORTS"`
+	}
+	assert.NoError(t, udotEnv.Bind(&cfg))
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, cfg.Labels)
+	assert.Equal(t, []uint16{80, 443}, cfg.Ports)
+
+	ports, err := Get[[]int](udotEnv, "SEP_PORTS")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{80, 443}, ports)
+}
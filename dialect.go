@@ -0,0 +1,30 @@
+package udotenv
+
+// Dialect is the flavour of the dotenv syntax used to parse the env files.
+type Dialect int
+
+const (
+	// DialectGodotenv is the syntax understood by godotenv: optional `export`
+	// prefixes, `=` or `:` separators, inline comments, and `$VAR` references
+	// expanded from the keys defined earlier in the same file.
+	DialectGodotenv Dialect = iota
+	// DialectSystemd is the syntax of the EnvironmentFile= files of systemd,
+	// so that a file is read the same way by a unit and by the binary run
+	// manually. See parseSystemd.
+	DialectSystemd
+	// DialectCompose is the syntax of the .env files of Docker Compose. It is
+	// the godotenv syntax with the interpolation rules of Compose: references
+	// are resolved from the environment, then from the keys defined earlier
+	// in the same file, `$$` is a literal `$`, and `${VAR:?err}`,
+	// `${VAR?err}`, `${VAR:+alt}` and `${VAR+alt}` are supported along with
+	// the defaults.
+	DialectCompose
+)
+
+// dialect returns the `Dialect` of the config.
+func (ue *UdotEnv) dialect() Dialect {
+	if ue.Config == nil {
+		return DialectGodotenv
+	}
+	return ue.Config.Dialect
+}
@@ -0,0 +1,51 @@
+package udotenv
+
+// ChangeKind tells how the value of a variable changed.
+type ChangeKind int
+
+const (
+	Added    ChangeKind = iota + 1 // the variable was not set before
+	Modified                       // the variable is set to another value
+	Removed                        // the variable is no longer set
+)
+
+// String returns "added", "modified" or "removed".
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Modified:
+		return "modified"
+	case Removed:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// Change describes how the value of a variable changed between two loads,
+// or between two sets of variables compared with Diff.
+type Change struct {
+	Kind ChangeKind
+	Old  string
+	New  string
+}
+
+// Diff returns the changes from the variables of a to the ones of b: the
+// keys only in b are Added, the ones only in a are Removed, and the ones
+// whose value differs are Modified. Unchanged keys are left out.
+func Diff(a, b map[string]string) map[string]Change {
+	changes := make(map[string]Change)
+	for k, v := range b {
+		if o, ok := a[k]; !ok {
+			changes[k] = Change{Kind: Added, New: v}
+		} else if o != v {
+			changes[k] = Change{Kind: Modified, Old: o, New: v}
+		}
+	}
+	for k, o := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = Change{Kind: Removed, Old: o}
+		}
+	}
+	return changes
+}
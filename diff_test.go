@@ -0,0 +1,27 @@
+package udotenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	changes := Diff(
+		map[string]string{"A": "1", "B": "1", "C": "1"},
+		map[string]string{"A": "1", "B": "2", "D": "1"},
+	)
+	assert.Equal(t, map[string]Change{
+		"B": {Kind: Modified, Old: "1", New: "2"},
+		"C": {Kind: Removed, Old: "1"},
+		"D": {Kind: Added, New: "1"},
+	}, changes)
+	assert.Empty(t, Diff(map[string]string{"A": "1"}, map[string]string{"A": "1"}))
+}
+
+func TestChangeKind_String(t *testing.T) {
+	assert.Equal(t, "added", Added.String())
+	assert.Equal(t, "modified", Modified.String())
+	assert.Equal(t, "removed", Removed.String())
+	assert.Equal(t, "unknown", ChangeKind(0).String())
+}
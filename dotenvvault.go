@@ -0,0 +1,116 @@
+package udotenv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	dotenvKeyEnv       = "DOTENV_KEY"
+	defaultDotenvVault = ".env.vault"
+)
+
+// dotenvVaultFile returns the path of the `.env.vault` file of the config.
+func (ue *UdotEnv) dotenvVaultFile() string {
+	if ue.Config != nil && ue.Config.DotenvVaultFile != "" {
+		return ue.Config.DotenvVaultFile
+	}
+	return defaultDotenvVault
+}
+
+// readDotenvVault reads the variables of the environment selected by
+// DOTENV_KEY from the `.env.vault` file. It reports false if DOTENV_KEY is not
+// set or the file does not exist, in which case the plaintext files are read
+// instead.
+func (ue *UdotEnv) readDotenvVault() (map[string]string, bool, error) {
+	keys := os.Getenv(dotenvKeyEnv)
+	if keys == "" {
+		return nil, false, nil
+	}
+
+	path := ue.dotenvVaultFile()
+	vault, err := ue.readFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	// several comma-separated keys may be given, e.g. during a key rotation
+	var errs []error
+	for _, key := range strings.Split(keys, ",") {
+		plain, err := decryptDotenvVault(vault, strings.TrimSpace(key))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		vars, err := ParseReader(strings.NewReader(plain))
+		if err != nil {
+			return nil, false, fmt.Errorf("error loading file '%s': %w", path, err)
+		}
+		return vars, true, nil
+	}
+	return nil, false, fmt.Errorf("error loading file '%s': %w", path, errors.Join(errs...))
+}
+
+// decryptDotenvVault decrypts the environment of vault selected by key, a
+// DOTENV_KEY of the form
+// `dotenv://:key_<hex>@dotenv.org/vault/.env.vault?environment=production`.
+// Each environment is stored in a DOTENV_VAULT_<ENVIRONMENT> variable as the
+// base64 of a 12-byte nonce followed by the AES-256-GCM ciphertext.
+func decryptDotenvVault(vault map[string]string, key string) (string, error) {
+	u, err := url.Parse(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", dotenvKeyEnv, err)
+	}
+
+	password, _ := u.User.Password()
+	secret, err := hex.DecodeString(strings.TrimPrefix(password, "key_"))
+	if err != nil || len(secret) < 32 {
+		return "", fmt.Errorf("invalid %s: missing key", dotenvKeyEnv)
+	}
+	secret = secret[len(secret)-32:]
+
+	environment := u.Query().Get("environment")
+	if environment == "" {
+		return "", fmt.Errorf("invalid %s: missing environment", dotenvKeyEnv)
+	}
+	name := "DOTENV_VAULT_" + strings.ToUpper(environment)
+	encoded, ok := vault[name]
+	if !ok {
+		return "", fmt.Errorf("cannot find environment %s in the vault", name)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", name, err)
+	}
+
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", fmt.Errorf("decrypting %s: ciphertext too short", name)
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting %s: %w", name, err)
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,58 @@
+package udotenv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encryptDotenvVault(t *testing.T, key, plain string) string {
+	secret, _ := hex.DecodeString(key)
+	block, err := aes.NewCipher(secret)
+	assert.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+
+	nonce := make([]byte, gcm.NonceSize())
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plain), nil))
+}
+
+func TestLoad_DotenvVault(t *testing.T) {
+	key := strings.Repeat("0123456789abcdef", 4)
+	other := strings.Repeat("fedcba9876543210", 4)
+
+	dir := t.TempDir()
+	vault := filepath.Join(dir, ".env.vault")
+	plain := filepath.Join(dir, ".env")
+	_ = os.WriteFile(vault, []byte(
+		`DOTENV_VAULT_PRODUCTION="`+encryptDotenvVault(t, key, "VAULT_A=production\n")+`"`+"\n"+
+			`DOTENV_VAULT_DEVELOPMENT="`+encryptDotenvVault(t, key, "VAULT_A=development\n")+`"`+"\n"), 0o644)
+	_ = os.WriteFile(plain, []byte("VAULT_A=plaintext\n"), 0o644)
+	defer os.Unsetenv("VAULT_A")
+
+	udotEnv := &UdotEnv{Config: &Config{DotenvVaultFile: vault}, EnvParam: stringSlice{plain}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "plaintext", os.Getenv("VAULT_A"))
+	os.Unsetenv("VAULT_A")
+
+	t.Setenv(dotenvKeyEnv, "dotenv://:key_"+other+"@dotenv.org/vault/.env.vault?environment=production, "+
+		"dotenv://:key_"+key+"@dotenv.org/vault/.env.vault?environment=production")
+	udotEnv = &UdotEnv{Config: &Config{DotenvVaultFile: vault}, EnvParam: stringSlice{plain}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "production", os.Getenv("VAULT_A"))
+
+	t.Setenv(dotenvKeyEnv, "dotenv://:key_"+other+"@dotenv.org/vault/.env.vault?environment=development")
+	assert.EqualError(t, udotEnv.Load(),
+		"error loading file '"+vault+"': decrypting DOTENV_VAULT_DEVELOPMENT: cipher: message authentication failed")
+
+	t.Setenv(dotenvKeyEnv, "dotenv://:key_"+key+"@dotenv.org/vault/.env.vault?environment=staging")
+	assert.EqualError(t, udotEnv.Load(),
+		"error loading file '"+vault+"': cannot find environment DOTENV_VAULT_STAGING in the vault")
+}
@@ -0,0 +1,48 @@
+package udotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicatePolicy defines what happens when an env file defines the same key
+// several times.
+type DuplicatePolicy int
+
+const (
+	// DuplicateLastWins keeps the last definition of the key, like godotenv.
+	DuplicateLastWins DuplicatePolicy = iota
+	// DuplicateFirstWins keeps the first definition of the key.
+	DuplicateFirstWins
+	// DuplicateError makes the load fail with a *ParseError.
+	DuplicateError
+)
+
+// duplicatePolicy returns the `DuplicateKeys` policy of the config.
+func (ue *UdotEnv) duplicatePolicy() DuplicatePolicy {
+	if ue.Config == nil {
+		return DuplicateLastWins
+	}
+	return ue.Config.DuplicateKeys
+}
+
+// duplicate applies the `DuplicateKeys` policy of the config to st, a
+// statement of doc, the content of the file at path. The keys defined so far
+// in doc are mapped to their line in defined. It reports whether st is
+// skipped.
+func (ue *UdotEnv) duplicate(doc *document, path string, st statement, defined map[string]int) (bool, error) {
+	line, ok := defined[st.key]
+	if !ok {
+		defined[st.key] = st.line
+		return false, nil
+	}
+
+	switch ue.duplicatePolicy() {
+	case DuplicateFirstWins:
+		return true, nil
+	case DuplicateError:
+		return false, &ParseError{File: path, Line: st.line, Column: strings.Index(doc.lines[st.line-1], st.key) + 1,
+			Text: st.key, Msg: fmt.Sprintf("%s is already defined on line %d", st.key, line)}
+	}
+	return false, nil
+}
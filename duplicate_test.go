@@ -0,0 +1,29 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_DuplicateKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("DUP_A=first\nDUP_B=1\n  DUP_A=second\n"), 0o644)
+
+	for policy, expected := range map[DuplicatePolicy]string{
+		DuplicateLastWins:  "second",
+		DuplicateFirstWins: "first",
+	} {
+		vars, err := (&UdotEnv{Config: &Config{DuplicateKeys: policy}}).readFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, vars["DUP_A"])
+	}
+
+	err := (&UdotEnv{Config: &Config{DuplicateKeys: DuplicateError}, EnvParam: stringSlice{path}}).Load()
+	assert.EqualError(t, err, "error loading file '"+path+"': line 3, column 3: DUP_A is already defined on line 1")
+	assert.ErrorIs(t, err, ErrParse)
+	_, ok := os.LookupEnv("DUP_B")
+	assert.False(t, ok)
+}
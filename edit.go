@@ -0,0 +1,160 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Document is an env file opened for editing. Its comments, blank lines and
+// the order of its keys are kept when it is saved.
+type Document struct {
+	path string
+	doc  *document
+}
+
+// Open reads the env file at path for editing. A missing file yields an
+// empty document, which Save creates.
+func Open(path string) (*Document, error) {
+	doc, err := readDocument(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		doc, err = parseDocument(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening file '%s': %w", path, err)
+	}
+	return &Document{path: path, doc: doc}, nil
+}
+
+// Path returns the path of the file of d.
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Keys returns the keys of d, in the order of the file.
+func (d *Document) Keys() []string {
+	keys := make([]string, 0, len(d.doc.statements))
+	for _, st := range d.doc.statements {
+		if !slices.Contains(keys, st.key) {
+			keys = append(keys, st.key)
+		}
+	}
+	return keys
+}
+
+// Get returns the value of key, as Load would read it from the file alone.
+func (d *Document) Get(key string) (string, bool) {
+	v, ok := d.doc.vars()[key]
+	return v, ok
+}
+
+// Vars returns the variables of d, as Load would read them from the file
+// alone.
+func (d *Document) Vars() map[string]string {
+	return d.doc.vars()
+}
+
+// Set sets the value of key. Every statement defining key is updated in
+// place; if there is none, a `KEY=value` line is appended. The value is
+// quoted as needed.
+func (d *Document) Set(key, value string) error {
+	if key == "" || strings.IndexFunc(key, func(r rune) bool { return !isKeyChar(r) }) != -1 {
+		return fmt.Errorf("invalid variable name %q", key)
+	}
+
+	found := false
+	// statements are replaced from the end, so that the line numbers of the
+	// ones before stay valid when a multi-line value shrinks
+	for i := len(d.doc.statements) - 1; i >= 0; i-- {
+		if st := d.doc.statements[i]; st.key == key {
+			d.doc.setValue(st, value)
+			found = true
+		}
+	}
+	if !found {
+		line := key + "=" + quoteValue(value)
+		if n := len(d.doc.lines); d.doc.lines[n-1] == "" {
+			d.doc.lines = slices.Insert(d.doc.lines, n-1, line)
+		} else {
+			d.doc.lines = append(d.doc.lines, line)
+		}
+	}
+	return d.reparse()
+}
+
+// Unset removes the statements defining key, and reports whether there were
+// any.
+func (d *Document) Unset(key string) bool {
+	found := false
+	for i := len(d.doc.statements) - 1; i >= 0; i-- {
+		if st := d.doc.statements[i]; st.key == key {
+			d.doc.lines = slices.Delete(d.doc.lines, st.line-1, st.endLine)
+			found = true
+		}
+	}
+	if found {
+		// removing whole statements cannot make the document invalid
+		_ = d.reparse()
+	}
+	return found
+}
+
+// Bytes returns the content of d.
+func (d *Document) Bytes() []byte {
+	return d.doc.bytes()
+}
+
+// Save writes d back to its file. The content is written to a temporary
+// file in the same directory, which then replaces the file, so that the file
+// is never left half-written.
+func (d *Document) Save() error {
+	return writeFileAtomic(d.path, d.doc.bytes())
+}
+
+// reparse parses the lines of d again, so that the positions of the
+// statements match the edited lines.
+func (d *Document) reparse() error {
+	doc, err := parseDocument(d.doc.bytes())
+	if err != nil {
+		return err
+	}
+	d.doc = doc
+	return nil
+}
+
+// writeFileAtomic writes data to the file at path through a temporary file
+// renamed over it. The mode of an existing file is kept; a new file gets
+// 0644.
+func writeFileAtomic(path string, data []byte) error {
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,73 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_Edit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("# database\n"+
+		"DB_HOST=localhost # local only\n"+
+		"DB_CERT=\"line1\n"+
+		"line2\"\n"+
+		"\n"+
+		"export DB_USER=admin\n"+
+		"DB_HOST=other\n"), 0o600)
+
+	doc, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DB_HOST", "DB_CERT", "DB_USER"}, doc.Keys())
+	v, ok := doc.Get("DB_HOST")
+	assert.True(t, ok)
+	assert.Equal(t, "other", v)
+
+	assert.NoError(t, doc.Set("DB_CERT", "short"))
+	assert.NoError(t, doc.Set("DB_HOST", "db.internal"))
+	assert.NoError(t, doc.Set("DB_PASSWORD", "s3cret value"))
+	assert.True(t, doc.Unset("DB_USER"))
+	assert.False(t, doc.Unset("MISSING"))
+	assert.EqualError(t, doc.Set("BAD KEY", "v"), `invalid variable name "BAD KEY"`)
+	assert.NoError(t, doc.Save())
+
+	src, _ := os.ReadFile(path)
+	assert.Equal(t, "# database\n"+
+		"DB_HOST=db.internal # local only\n"+
+		"DB_CERT=short\n"+
+		"\n"+
+		"DB_HOST=db.internal\n"+
+		"DB_PASSWORD=\"s3cret value\"\n", string(src))
+	assert.Equal(t, map[string]string{"DB_HOST": "db.internal", "DB_CERT": "short", "DB_PASSWORD": "s3cret value"}, doc.Vars())
+
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, entries, 1)
+}
+
+func TestDocument_New(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+
+	doc, err := Open(path)
+	assert.NoError(t, err)
+	assert.Empty(t, doc.Keys())
+	assert.NoError(t, doc.Set("NEW_KEY", "value"))
+	assert.NoError(t, doc.Save())
+
+	src, _ := os.ReadFile(path)
+	assert.Equal(t, "NEW_KEY=value\n", string(src))
+}
+
+func TestDocument_OpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("INVALID\n"), 0o644)
+
+	_, err := Open(path)
+	assert.EqualError(t, err, "error opening file '"+path+"': line 1, column 1: missing '=' after \"INVALID\"")
+}
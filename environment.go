@@ -0,0 +1,114 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+)
+
+// Environment holds a set of variables apart from the process environment,
+// so that they can be consumed without global mutation and applied to the
+// process only on purpose. It is safe for concurrent use.
+type Environment struct {
+	mu       sync.RWMutex
+	vars     map[string]string
+	overload map[string]bool // variables that overwrite the process environment
+}
+
+// NewEnvironment returns an Environment holding a copy of vars. Its variables
+// overwrite the process environment when applied.
+func NewEnvironment(vars map[string]string) *Environment {
+	env := &Environment{vars: maps.Clone(vars), overload: make(map[string]bool, len(vars))}
+	if env.vars == nil {
+		env.vars = make(map[string]string)
+	}
+	for k := range env.vars {
+		env.overload[k] = true
+	}
+	return env
+}
+
+// Environment reads the env files like Read and returns their variables as an
+// Environment, without touching the process environment. Applying it follows
+// the overload rules of Load.
+func (ue *UdotEnv) Environment(paths ...string) (*Environment, error) {
+	ue.mu.Lock()
+	merged, err := ue.read(paths)
+	ue.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	env := &Environment{vars: make(map[string]string, len(merged)), overload: make(map[string]bool, len(merged))}
+	for k, e := range merged {
+		env.vars[k] = e.value
+		env.overload[k] = e.overload
+	}
+	return env, nil
+}
+
+// Get returns the value of the variable key and whether it is set.
+func (env *Environment) Get(key string) (string, bool) {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+	v, ok := env.vars[key]
+	return v, ok
+}
+
+// Set sets the variable key to value. It overwrites the process environment
+// when applied.
+func (env *Environment) Set(key, value string) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	env.vars[key] = value
+	env.overload[key] = true
+}
+
+// Unset removes the variable key.
+func (env *Environment) Unset(key string) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	delete(env.vars, key)
+	delete(env.overload, key)
+}
+
+// Vars returns a copy of the variables.
+func (env *Environment) Vars() map[string]string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+	return maps.Clone(env.vars)
+}
+
+// Environ returns the variables as "KEY=value" entries sorted by key, in the
+// form of os.Environ and exec.Cmd.Env.
+func (env *Environment) Environ() []string {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+
+	entries := make([]string, 0, len(env.vars))
+	for _, k := range sortedKeys(env.vars) {
+		entries = append(entries, k+"="+env.vars[k])
+	}
+	return entries
+}
+
+// Apply sets the variables in the process environment. Variables read from
+// files that do not overload the environment are skipped if already set.
+// Every variable is applied before an error is returned.
+func (env *Environment) Apply() error {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+
+	var errs []error
+	for _, k := range sortedKeys(env.vars) {
+		if _, ok := os.LookupEnv(k); ok && !env.overload[k] {
+			continue
+		}
+		if err := os.Setenv(k, env.vars[k]); err != nil {
+			errs = append(errs, fmt.Errorf("setting %s: %w", k, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("ENVIRON_A=1\nENVIRON_KEPT=file\n"), 0o644)
+	t.Setenv("ENVIRON_KEPT", "env")
+
+	env, err := (&UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}).Environment()
+	assert.NoError(t, err)
+	_, ok := os.LookupEnv("ENVIRON_A")
+	assert.False(t, ok)
+
+	v, ok := env.Get("ENVIRON_KEPT")
+	assert.True(t, ok)
+	assert.Equal(t, "file", v)
+
+	env.Set("ENVIRON_B", "set")
+	env.Unset("ENVIRON_A")
+	assert.Equal(t, []string{"ENVIRON_B=set", "ENVIRON_KEPT=file"}, env.Environ())
+	assert.Equal(t, map[string]string{"ENVIRON_B": "set", "ENVIRON_KEPT": "file"}, env.Vars())
+
+	defer os.Unsetenv("ENVIRON_B")
+	assert.NoError(t, env.Apply())
+	assert.Equal(t, "set", os.Getenv("ENVIRON_B"))
+	assert.Equal(t, "env", os.Getenv("ENVIRON_KEPT"))
+
+	env.Set("ENVIRON_KEPT", "set")
+	assert.NoError(t, env.Apply())
+	assert.Equal(t, "set", os.Getenv("ENVIRON_KEPT"))
+}
+
+func TestNewEnvironment(t *testing.T) {
+	t.Setenv("NEWENV_A", "env")
+
+	env := NewEnvironment(map[string]string{"NEWENV_A": "1"})
+	assert.NoError(t, env.Apply())
+	assert.Equal(t, "1", os.Getenv("NEWENV_A"))
+
+	assert.Empty(t, NewEnvironment(nil).Environ())
+}
@@ -0,0 +1,55 @@
+package udotenv
+
+import (
+	"fmt"
+	"os"
+)
+
+// ErrorHandling defines how New and Load report errors, like the
+// flag.ErrorHandling of a flag set.
+type ErrorHandling int
+
+const (
+	// ErrorHandlingDefault panics on the errors of New and returns the
+	// errors of Load, unless the deprecated `PanicOnError` is set.
+	ErrorHandlingDefault ErrorHandling = iota
+	// ReturnOnError returns the errors. Since New has no error result, its
+	// errors are returned by Load.
+	ReturnOnError
+	// ExitOnError prints the error to stderr and exits with status 2.
+	ExitOnError
+	// PanicOnError panics with the error.
+	PanicOnError
+)
+
+// failNew reports err, an error of New, according to the `ErrorHandling` of
+// the config: it keeps err for Load with ReturnOnError, exits with
+// ExitOnError and panics with err otherwise.
+func (ue *UdotEnv) failNew(err error) {
+	switch ue.errorHandling() {
+	case ReturnOnError:
+		if ue.err == nil {
+			ue.err = err
+		}
+	case ExitOnError:
+		fmt.Fprintln(os.Stderr, "udotenv:", err)
+		exit(2)
+	default:
+		panic(err)
+	}
+}
+
+// errorHandling resolves the `ErrorHandling` of the config, taking the
+// deprecated `PanicOnError` into account.
+func (ue *UdotEnv) errorHandling() ErrorHandling {
+	if ue.Config == nil {
+		return ErrorHandlingDefault
+	}
+	if ue.Config.ErrorHandling == ErrorHandlingDefault && ue.Config.PanicOnError {
+		return PanicOnError
+	}
+	return ue.Config.ErrorHandling
+}
+
+// exit is os.Exit, replaced in tests.
+var exit = os.Exit
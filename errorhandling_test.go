@@ -0,0 +1,45 @@
+package udotenv
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandling_Load(t *testing.T) {
+	newUdotEnv := func(handling ErrorHandling) *UdotEnv {
+		return &UdotEnv{Config: &Config{ErrorHandling: handling}, EnvParam: stringSlice{".missing.env"}}
+	}
+
+	assert.ErrorIs(t, newUdotEnv(ErrorHandlingDefault).Load(), ErrFileNotFound)
+	assert.ErrorIs(t, newUdotEnv(ReturnOnError).Load(), ErrFileNotFound)
+	assert.Panics(t, func() { _ = newUdotEnv(PanicOnError).Load() })
+
+	var code int
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+	assert.Error(t, newUdotEnv(ExitOnError).Load())
+	assert.Equal(t, 2, code)
+
+	udotEnv := newUdotEnv(ReturnOnError)
+	udotEnv.Config.PanicOnError = true
+	assert.NotPanics(t, func() { _ = udotEnv.Load() })
+}
+
+func TestErrorHandling_New(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("o", false, "")
+
+	config := GetDefaultConfig()
+	config.ErrorHandling = ReturnOnError
+	udotEnv := NewWithFlagSet(fs, config)
+	assert.ErrorIs(t, udotEnv.Load(), ErrInvalidConfig)
+	assert.ErrorIs(t, udotEnv.Load(), ErrInvalidConfig)
+
+	config = GetDefaultConfig()
+	config.ErrorHandling = PanicOnError
+	assert.Panics(t, func() { NewWithFlagSet(fs, config) })
+	assert.Panics(t, func() { NewWithFlagSet(fs, nil) })
+}
@@ -0,0 +1,44 @@
+package udotenv
+
+import "errors"
+
+// Sentinel errors matching the errors of udotenv by their cause with
+// errors.Is. The errors keep their own messages, which name the file or the
+// keys involved.
+var (
+	// ErrFileNotFound matches the errors about an env file that does not
+	// exist, including URLs answering 404. They match fs.ErrNotExist as well.
+	ErrFileNotFound = errors.New("env file not found")
+	// ErrParse matches the errors about malformed content, including
+	// *ParseError values and invalid JSON, YAML or TOML files.
+	ErrParse = errors.New("malformed env content")
+	// ErrValidation matches the errors about values rejected by the config:
+	// missing required keys, *ValidationError values, exceeded limits and
+	// invalid booleans.
+	ErrValidation = errors.New("invalid env values")
+	// ErrInvalidConfig matches the *ConfigError values reported for a Config
+	// whose flags cannot be registered.
+	ErrInvalidConfig = errors.New("invalid config")
+)
+
+// sentinelError is an error that also matches a sentinel error.
+type sentinelError struct {
+	err      error
+	sentinel error
+}
+
+func (e *sentinelError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sentinelError) Unwrap() []error {
+	return []error{e.err, e.sentinel}
+}
+
+// withSentinel returns err made to match sentinel, or nil if err is nil.
+func withSentinel(err, sentinel error) error {
+	if err == nil || errors.Is(err, sentinel) {
+		return err
+	}
+	return &sentinelError{err: err, sentinel: sentinel}
+}
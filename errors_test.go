@@ -0,0 +1,67 @@
+package udotenv
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors_FileNotFound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.env")
+
+	err := (&UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}).Load()
+	assert.EqualError(t, err, "error loading file '"+path+"': open "+path+": no such file or directory")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NotErrorIs(t, err, ErrParse)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err = (&UdotEnv{Config: &Config{}, EnvParam: stringSlice{server.URL + "/app.env"}}).Load()
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestErrors_Parse(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".env":     "BAD KEY=1\n",
+		"app.json": "{",
+		"app.yaml": "a: [",
+		"app.toml": "a = ",
+	} {
+		path := filepath.Join(dir, name)
+		_ = os.WriteFile(path, []byte(content), 0o644)
+
+		err := (&UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}).Load()
+		assert.ErrorIs(t, err, ErrParse, name)
+		assert.NotErrorIs(t, err, ErrFileNotFound, name)
+	}
+}
+
+func TestErrors_Validation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("ERRV_A=1\nERRV_B=maybe\n"), 0o644)
+	defer os.Unsetenv("ERRV_A")
+
+	for name, config := range map[string]*Config{
+		"required": {RequiredKeys: []string{"ERRV_MISSING"}},
+		"limits":   {MaxKeys: 1},
+		"booleans": {NormalizeBools: []string{"ERRV_B"}},
+	} {
+		err := (&UdotEnv{Config: config, EnvParam: stringSlice{path}}).Load()
+		assert.ErrorIs(t, err, ErrValidation, name)
+		assert.NotErrorIs(t, err, ErrParse, name)
+	}
+
+	assert.ErrorIs(t, &ValidationError{}, ErrValidation)
+	assert.True(t, errors.Is((&UdotEnv{}).RequireExactlyOne([]string{"ERRV_X", "ERRV_Y"}), ErrValidation))
+}
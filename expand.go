@@ -0,0 +1,228 @@
+package udotenv
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// expanding reports whether the variables are expanded across files, see
+// `Config.Expand`.
+func (ue *UdotEnv) expanding() bool {
+	return ue.Config != nil && ue.Config.Expand
+}
+
+// escapeDollars escapes the `$` of the values of vars, so that values which
+// do not come from env files, e.g. secrets, are never expanded.
+func escapeDollars(vars map[string]string) map[string]string {
+	for k, v := range vars {
+		vars[k] = strings.ReplaceAll(v, "$", `\$`)
+	}
+	return vars
+}
+
+// expander resolves the references of the merged variables.
+type expander struct {
+	ue       *UdotEnv
+	merged   map[string]entry
+	resolved map[string]string
+	visiting []string // keys being resolved, to detect cycles
+}
+
+// expand replaces the `$VAR`, `${VAR}`, `${VAR:-default}` and
+// `${VAR-default}` references in the merged values. A reference resolves to
+// the value the variable will have once loaded: the merged value if it is
+// applied, the value from the environment otherwise. A variable referring to
+// itself, as in `PATH=$PATH:/opt/bin`, gets the value from the environment.
+// Keys are resolved in sorted order and a reference cycle is an error.
+func (ue *UdotEnv) expand(merged map[string]entry) error {
+	x := &expander{ue: ue, merged: merged, resolved: make(map[string]string, len(merged))}
+	for _, k := range sortedKeys(merged) {
+		v, err := x.resolve(k)
+		if err != nil {
+			return err
+		}
+		e := merged[k]
+		e.value = v
+		merged[k] = e
+	}
+	return nil
+}
+
+// resolve returns the expanded value of the merged variable k.
+func (x *expander) resolve(k string) (string, error) {
+	if v, ok := x.resolved[k]; ok {
+		return v, nil
+	}
+	for i, key := range x.visiting {
+		if key == k {
+			cycle := append(slices.Clone(x.visiting[i:]), k)
+			return "", fmt.Errorf("reference cycle in variable expansion: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	x.visiting = append(x.visiting, k)
+	v, err := x.expandValue(x.merged[k].value, k)
+	x.visiting = x.visiting[:len(x.visiting)-1]
+	if err != nil {
+		return "", err
+	}
+	x.resolved[k] = v
+	return v, nil
+}
+
+// lookup returns the value the variable k will have once loaded, as seen
+// from the value of the variable self. Since the merged variables are
+// expanded before they are filtered, whether k overwrites the environment is
+// decided with the `OverloadOnly` and `ProtectedKeys` of the config already
+// applied.
+func (x *expander) lookup(k, self string) (string, bool, error) {
+	env, inEnv := os.LookupEnv(k)
+	e, ok := x.merged[k]
+	if !ok || k == self || (inEnv && !x.ue.overloadsKey(k, e.overload) && !x.ue.owned[k]) {
+		return env, inEnv, nil
+	}
+	v, err := x.resolve(k)
+	return v, true, err
+}
+
+// expandValue expands the references in s, the value of the variable self.
+func (x *expander) expandValue(s, self string) (string, error) {
+	lookup := func(k string) (string, bool, error) {
+		return x.lookup(k, self)
+	}
+	return interpolate(s, self, lookup, x.ue.dialect() == DialectCompose)
+}
+
+// lookupFunc returns the value of the variable k, and whether it is set.
+type lookupFunc func(k string) (string, bool, error)
+
+// interpolate expands the references in s, the value of the variable self,
+// with the values returned by lookup. With compose, the syntax of Docker
+// Compose is supported as well: `$$` is a literal `$`, and `${VAR:?err}`,
+// `${VAR?err}`, `${VAR:+alt}` and `${VAR+alt}` are allowed.
+func interpolate(s, self string, lookup lookupFunc, compose bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		if compose && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := matchingBrace(s, i+1)
+			if end == -1 {
+				return "", fmt.Errorf("unterminated reference in value of %s", self)
+			}
+			v, err := interpolateBraced(s[i+2:end], self, lookup, compose)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = end
+			continue
+		}
+
+		n := nameLen(s[i+1:])
+		if n == 0 {
+			b.WriteByte(c)
+			continue
+		}
+		v, _, err := lookup(s[i+1 : i+1+n])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+		i += n
+	}
+	return b.String(), nil
+}
+
+// interpolateBraced expands the content of a `${...}` reference.
+func interpolateBraced(ref, self string, lookup lookupFunc, compose bool) (string, error) {
+	n := nameLen(ref)
+	if n == 0 {
+		return "", fmt.Errorf("invalid reference ${%s} in value of %s", ref, self)
+	}
+	name, rest := ref[:n], ref[n:]
+
+	v, set, err := lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	op, arg := rest, ""
+	if i := strings.IndexAny(rest, "-?+"); i != -1 && i <= 1 && (i == 0 || rest[0] == ':') {
+		op, arg = rest[:i+1], rest[i+1:]
+	}
+	if !compose && op != "" && op != ":-" && op != "-" {
+		return "", fmt.Errorf("invalid reference ${%s} in value of %s", ref, self)
+	}
+
+	switch op {
+	case "":
+		return v, nil
+	case ":-", "-":
+		if !set || (op == ":-" && v == "") {
+			return interpolate(arg, self, lookup, compose)
+		}
+		return v, nil
+	case ":?", "?":
+		if !set || (op == ":?" && v == "") {
+			if arg == "" {
+				return "", withSentinel(fmt.Errorf("required variable %s is missing a value", name), ErrValidation)
+			}
+			return "", withSentinel(fmt.Errorf("required variable %s is missing a value: %s", name, arg), ErrValidation)
+		}
+		return v, nil
+	case ":+", "+":
+		if set && (op == "+" || v != "") {
+			return interpolate(arg, self, lookup, compose)
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("invalid reference ${%s} in value of %s", ref, self)
+}
+
+// matchingBrace returns the index of the brace closing the one at index
+// open of s, or -1.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// nameLen returns the length of the variable name at the start of s.
+func nameLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
@@ -0,0 +1,80 @@
+package udotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Expand(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.env")
+	second := filepath.Join(dir, "second.env")
+	_ = os.WriteFile(first, []byte(
+		"EXPAND_URL=http://${EXPAND_HOST}:${EXPAND_PORT:-8080}/$EXPAND_NAME\n"+
+			"EXPAND_LITERAL='$EXPAND_HOST'\n"+
+			"EXPAND_ESCAPED=\"\\$EXPAND_HOST\"\n"+
+			"EXPAND_PATH=$EXPAND_PATH:/opt/bin\n"+
+			"EXPAND_UNSET=${EXPAND_MISSING-none}|${EXPAND_EMPTY-unused}|${EXPAND_EMPTY:-empty}\n"), 0o644)
+	_ = os.WriteFile(second, []byte("EXPAND_HOST=localhost\nEXPAND_EMPTY=\n"), 0o644)
+	for _, k := range []string{"EXPAND_URL", "EXPAND_LITERAL", "EXPAND_ESCAPED", "EXPAND_PATH", "EXPAND_UNSET",
+		"EXPAND_HOST", "EXPAND_EMPTY", "EXPAND_SECRET"} {
+		defer os.Unsetenv(k)
+	}
+	t.Setenv("EXPAND_NAME", "app")
+
+	src := SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"EXPAND_SECRET": "pa$$word"}, nil
+	})
+	udotEnv := &UdotEnv{
+		Config:   &Config{Expand: true, Sources: []Source{src}},
+		EnvParam: stringSlice{first, second},
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "http://localhost:8080/app", os.Getenv("EXPAND_URL"))
+	assert.Equal(t, "$EXPAND_HOST", os.Getenv("EXPAND_LITERAL"))
+	assert.Equal(t, "$EXPAND_HOST", os.Getenv("EXPAND_ESCAPED"))
+	assert.Equal(t, ":/opt/bin", os.Getenv("EXPAND_PATH"))
+	assert.Equal(t, "none||empty", os.Getenv("EXPAND_UNSET"))
+	assert.Equal(t, "pa$$word", os.Getenv("EXPAND_SECRET"))
+}
+
+func TestLoad_ExpandEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("EXPAND_ENV_HOST=file\nEXPAND_ENV_URL=http://$EXPAND_ENV_HOST\n"), 0o644)
+	defer os.Unsetenv("EXPAND_ENV_URL")
+	t.Setenv("EXPAND_ENV_HOST", "env")
+
+	udotEnv := &UdotEnv{Config: &Config{Expand: true}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "http://env", os.Getenv("EXPAND_ENV_URL"))
+
+	udotEnv = &UdotEnv{Config: &Config{Expand: true}, EnvParam: stringSlice{path}, OverloadParam: true}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "http://file", os.Getenv("EXPAND_ENV_URL"))
+}
+
+func TestLoad_ExpandCycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("CYCLE_A=${CYCLE_B}\nCYCLE_B=${CYCLE_C:-$CYCLE_A}\nCYCLE_C=\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{Expand: true}, EnvParam: stringSlice{path}}
+	assert.EqualError(t, udotEnv.Load(), "reference cycle in variable expansion: CYCLE_A -> CYCLE_B -> CYCLE_A")
+	_, ok := os.LookupEnv("CYCLE_C")
+	assert.False(t, ok)
+}
+
+func TestLoad_ExpandInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("INVALID_A=${INVALID_B\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{Expand: true}, EnvParam: stringSlice{path}}
+	assert.EqualError(t, udotEnv.Load(), "unterminated reference in value of INVALID_A")
+
+	_ = os.WriteFile(path, []byte("INVALID_A=${INVALID_B:?}\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "invalid reference ${INVALID_B:?} in value of INVALID_A")
+}
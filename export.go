@@ -0,0 +1,92 @@
+package udotenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Marshal formats vars as the content of an env file, with one `KEY=value`
+// line per variable, sorted by key. Values are written bare when they are
+// made of safe characters and double-quoted with escapes otherwise, so that
+// parsing the output yields vars again. An error is returned if a key is not
+// a valid variable name.
+func Marshal(vars map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	for _, k := range sortedKeys(vars) {
+		if k == "" || strings.IndexFunc(k, func(r rune) bool { return !isKeyChar(r) }) != -1 {
+			return nil, fmt.Errorf("invalid variable name %q", k)
+		}
+		b.WriteString(k + "=" + quoteValue(vars[k]) + "\n")
+	}
+	return b.Bytes(), nil
+}
+
+// WriteFile writes the variables read by the last call to Load to the env
+// file at path, in the format of Marshal. As with ExportScript, the values
+// are the ones in effect after the load.
+func (ue *UdotEnv) WriteFile(path string) error {
+	src, err := Marshal(ue.loadedVars())
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, src)
+}
+
+// ExportScript returns the variables read by the last call to Load as a POSIX
+// shell script of `export KEY='value'` lines, sorted by key. The values are
+// the ones in effect after the load, so variables that were already set and
+// not overloaded keep their previous values. An error is returned, and no
+// script, if a key is not a portable shell name: letters, digits and
+// underscores, not starting with a digit. Such keys may come from Sources,
+// registered schemes or the `.` that env files accept in names.
+//
+// The output is safe to pass to `eval`:
+//
+//	eval "$(myapp env --export)"
+func (ue *UdotEnv) ExportScript() (string, error) {
+	var b strings.Builder
+	if err := ue.WriteExportScript(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteExportScript writes the output of ExportScript to w. Nothing is
+// written if a key is not a portable shell name.
+func (ue *UdotEnv) WriteExportScript(w io.Writer) error {
+	vars := ue.loadedVars()
+	keys := sortedKeys(vars)
+	for _, k := range keys {
+		if !isShellName(k) {
+			return fmt.Errorf("cannot export %q: not a valid shell variable name", k)
+		}
+	}
+	for _, k := range keys {
+		if _, err := io.WriteString(w, "export "+k+"="+shellQuote(vars[k])+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isShellName reports whether k is a portable shell variable name.
+func isShellName(k string) bool {
+	if k == "" || (k[0] >= '0' && k[0] <= '9') {
+		return false
+	}
+	for _, r := range k {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// shellQuote wraps s in single quotes. A single quote inside s is written as a
+// closing quote, an escaped quote and an opening quote, which POSIX shells
+// join back into one word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,96 @@
+package udotenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportScript(t *testing.T) {
+	_ = godotenv.Write(map[string]string{
+		"EXPORT_B": "it's here",
+		"EXPORT_A": "plain",
+	}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("EXPORT_A")
+	defer os.Unsetenv("EXPORT_B")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+
+	script, err := udotEnv.ExportScript()
+	assert.NoError(t, err)
+	assert.Equal(t, "export EXPORT_A='plain'\nexport EXPORT_B='it'\\''s here'\n", script)
+}
+
+func TestExportScript_NotLoaded(t *testing.T) {
+	udotEnv := &UdotEnv{}
+
+	script, err := udotEnv.ExportScript()
+	assert.NoError(t, err)
+	assert.Empty(t, script)
+}
+
+func TestExportScript_InvalidKeys(t *testing.T) {
+	for _, key := range []string{"EXPORT.DOTTED", "EXPORT_X;id", "1EXPORT"} {
+		defer os.Unsetenv(key)
+		udotEnv := &UdotEnv{Config: &Config{Sources: []Source{SourceFunc(func(context.Context) (map[string]string, error) {
+			return map[string]string{"EXPORT_OK": "1", key: "x"}, nil
+		})}}}
+		assert.NoError(t, udotEnv.Load())
+
+		var b bytes.Buffer
+		err := udotEnv.WriteExportScript(&b)
+		assert.EqualError(t, err, fmt.Sprintf("cannot export %q: not a valid shell variable name", key))
+		assert.Empty(t, b.String())
+		_, err = udotEnv.ExportScript()
+		assert.Error(t, err)
+	}
+	os.Unsetenv("EXPORT_OK")
+}
+
+func TestMarshal(t *testing.T) {
+	vars := map[string]string{
+		"B_PLAIN":   "postgres://user@host:5432/db",
+		"A_SPACES":  "hello world",
+		"C_QUOTES":  `say "hi" it's`,
+		"D_DOLLAR":  "pa$$word ${HOME}",
+		"E_NEWLINE": "line1\nline2\r",
+		"F_HASH":    "a #b",
+		"G_EMPTY":   "",
+		"H_SLASH":   `C:\path`,
+	}
+
+	src, err := Marshal(vars)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(src, []byte("A_SPACES=\"hello world\"\nB_PLAIN=postgres://user@host:5432/db\n")))
+
+	parsed, err := ParseReader(bytes.NewReader(src))
+	assert.NoError(t, err)
+	assert.Equal(t, vars, parsed)
+
+	_, err = Marshal(map[string]string{"BAD KEY": "v"})
+	assert.EqualError(t, err, `invalid variable name "BAD KEY"`)
+}
+
+func TestWriteFile(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"WRITE_A": "one two", "WRITE_B": "2"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("WRITE_A")
+	defer os.Unsetenv("WRITE_B")
+	t.Setenv("WRITE_B", "kept")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}}
+	assert.NoError(t, udotEnv.Load())
+
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, udotEnv.WriteFile(path))
+	src, _ := os.ReadFile(path)
+	assert.Equal(t, "WRITE_A=\"one two\"\nWRITE_B=kept\n", string(src))
+}
@@ -0,0 +1,171 @@
+package udotenv
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// readFile parses the env file at path, which may be an http:// or https://
+// URL or the reference of a registered scheme (see RegisterScheme). Files
+// with a `.gz` extension are decompressed before parsing; with `Decompress`
+// set in the config, so are the files starting with the gzip magic header.
+func (ue *UdotEnv) readFile(path string) (map[string]string, error) {
+	var vars map[string]string
+	var err error
+	if isURL(path) {
+		vars, err = ue.fetch(path)
+	} else if factory, ok := lookupScheme(path); ok {
+		vars, err = fetchScheme(ue.loadContext(), factory, path)
+		if err == nil && ue.expanding() {
+			vars = escapeDollars(vars)
+		}
+	} else {
+		vars, err = ue.parseFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading file '%s': %w", path, err)
+	}
+	return vars, nil
+}
+
+// parseFile reads, decompresses and parses the env file at path.
+func (ue *UdotEnv) parseFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	defer f.Close()
+	return ue.parse(path, contextReader{ue.loadContext(), f})
+}
+
+// contextReader is an io.Reader that fails with the error of ctx once it is
+// done, so that reading a large file stops on cancellation.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// parse decompresses and parses the env content read from r, decrypting it
+// first if it was encrypted with age or SOPS. The path is used to detect
+// the format (see Format) as well as compressed and age-encrypted files by
+// their extension, and to resolve the includes.
+func (ue *UdotEnv) parse(path string, rd io.Reader) (map[string]string, error) {
+	var stack []string
+	if path != "" && !isRemote(path) {
+		if abs, err := filepath.Abs(path); err == nil {
+			stack = append(stack, abs)
+		}
+	}
+	return ue.parseIncluded(path, rd, stack, nil)
+}
+
+// parseIncluded parses the env content read from r, which is included by the
+// files of stack. Its includes are read from fsys, or from the host if it is
+// nil. See parse.
+func (ue *UdotEnv) parseIncluded(path string, rd io.Reader, stack []string, fsys fs.FS) (map[string]string, error) {
+	if filepath.Ext(path) == ageExt {
+		var err error
+		if rd, err = ue.decryptAge(rd); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := ue.decompress(path, bufio.NewReader(rd))
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if isSOPS(src) {
+		if src, err = ue.decryptSOPS(src); err != nil {
+			return nil, err
+		}
+	}
+
+	if f := ue.format(path); f != FormatDotenv {
+		return ue.parseStructured(f, src)
+	}
+	if ue.dialect() == DialectSystemd {
+		vars, err := parseSystemd(src)
+		if err != nil {
+			return nil, withFile(err, path)
+		}
+		if ue.expanding() {
+			vars = escapeDollars(vars)
+		}
+		return vars, nil
+	}
+
+	doc, err := parseDocument(src)
+	if err != nil {
+		return nil, withFile(err, path)
+	}
+
+	if ue.Config != nil && ue.Config.RejectPaddedValues {
+		for _, st := range doc.statements {
+			if st.padded {
+				return nil, &ParseError{File: path, Line: st.line, Column: st.col + 1, Text: st.raw,
+					Msg: fmt.Sprintf("value of %s has unquoted leading or trailing whitespace", st.key)}
+			}
+		}
+	}
+	if ue.Config != nil && ue.Config.AllowCommandSubstitution {
+		if err := ue.substituteCommands(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return ue.docVars(doc, path, stack, fsys)
+}
+
+// decompress wraps r in a gzip reader if the file at path is compressed.
+func (ue *UdotEnv) decompress(path string, r *bufio.Reader) (io.Reader, error) {
+	compressed := filepath.Ext(path) == ".gz"
+	if !compressed && ue.Config != nil && ue.Config.Decompress {
+		header, _ := r.Peek(len(gzipMagic))
+		compressed = bytes.Equal(header, gzipMagic)
+	}
+
+	if !compressed {
+		return r, nil
+	}
+	return gzip.NewReader(r)
+}
+
+// withFile sets the file of err to path if it is a *ParseError.
+func withFile(err error, path string) error {
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.File == "" {
+		pe.File = path
+	}
+	return err
+}
+
+// notFound makes err match ErrFileNotFound if it is about a missing file.
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return withSentinel(err, ErrFileNotFound)
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package udotenv
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	w := gzip.NewWriter(f)
+	_, err = w.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+}
+
+func TestLoad_GzipExtension(t *testing.T) {
+	writeGzip(t, ".test.env.gz", "GZIP_KEY=compressed\n")
+	defer os.Remove(".test.env.gz")
+	defer os.Unsetenv("GZIP_KEY")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env.gz"}}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "compressed", os.Getenv("GZIP_KEY"))
+}
+
+func TestLoad_GzipMagicHeader(t *testing.T) {
+	writeGzip(t, ".test.env", "GZIP_MAGIC_KEY=compressed\n")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("GZIP_MAGIC_KEY")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{Decompress: true},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "compressed", os.Getenv("GZIP_MAGIC_KEY"))
+}
+
+func TestLoad_PlainWithDecompress(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("PLAIN_KEY=plain\n"), 0o644)
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("PLAIN_KEY")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{Decompress: true},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "plain", os.Getenv("PLAIN_KEY"))
+}
+
+func TestLoad_RejectPaddedValues(t *testing.T) {
+	for content, col := range map[string]string{"PADDED_HOST= example.com\n": "14", "PADDED_HOST=example.com \n": "13"} {
+		_ = os.WriteFile(".test.env", []byte("PADDED_OK=fine\n"+content), 0o644)
+
+		udotEnv := &UdotEnv{
+			Config:   &Config{RejectPaddedValues: true},
+			EnvParam: stringSlice{".test.env"},
+		}
+
+		assert.EqualError(t, udotEnv.Load(),
+			"error loading file '.test.env': line 2, column "+col+": value of PADDED_HOST has unquoted leading or trailing whitespace")
+	}
+	os.Remove(".test.env")
+}
+
+func TestLoad_RejectPaddedValuesAllowsQuotes(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("PADDED_QUOTED=\" spaced \"\nPADDED_ALIGNED=value   # comment\nPADDED_EMPTY=  \n"), 0o644)
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("PADDED_QUOTED")
+	defer os.Unsetenv("PADDED_ALIGNED")
+	defer os.Unsetenv("PADDED_EMPTY")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{RejectPaddedValues: true},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, " spaced ", os.Getenv("PADDED_QUOTED"))
+	assert.Equal(t, "value", os.Getenv("PADDED_ALIGNED"))
+}
+
+func TestContextReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := contextReader{ctx, strings.NewReader("A=1\n")}
+
+	p := make([]byte, 2)
+	n, err := r.Read(p)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, err)
+
+	cancel()
+	_, err = r.Read(p)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,98 @@
+package udotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// resolveFileRefs adds to pending the variables referenced by the keys ending
+// with the `FileRefSuffix` of the config: the content of the file named by
+// DB_PASSWORD_FILE becomes the value of DB_PASSWORD, as with the secrets
+// mounted by Docker. The references are taken from pending and, for the
+// targets declared by the config (see knownKeys), from the environment, so
+// that standard variables such as SSL_CERT_FILE are left alone. A target
+// already in the environment is only overwritten if udotenv set it or the
+// reference overloads it, and a target also defined in the files makes the
+// load fail. Targets not matching the `KeyPattern` of the config are skipped.
+func (ue *UdotEnv) resolveFileRefs(merged map[string]entry, pending map[string]string) error {
+	if ue.Config == nil || ue.Config.FileRefSuffix == "" {
+		return nil
+	}
+	suffix := ue.Config.FileRefSuffix
+
+	refs := make(map[string]string)
+	known := ue.knownKeys()
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.HasSuffix(k, suffix) && known[strings.TrimSuffix(k, suffix)] {
+			refs[k] = v
+		}
+	}
+	for k, v := range pending {
+		if strings.HasSuffix(k, suffix) {
+			refs[k] = v
+		}
+	}
+
+	for _, k := range sortedKeys(refs) {
+		target := strings.TrimSuffix(k, suffix)
+		if target == "" || (ue.Config.KeyPattern != nil && !ue.Config.KeyPattern.MatchString(target)) {
+			continue
+		}
+		if _, ok := merged[target]; ok {
+			return withSentinel(fmt.Errorf("both %s and %s are defined", target, k), ErrValidation)
+		}
+		if _, ok := os.LookupEnv(target); ok && !merged[k].overload && !ue.owned[target] {
+			continue
+		}
+		if merged[k].overload && ue.protected(target) {
+			continue
+		}
+
+		content, err := readFileRef(k, target, refs[k])
+		if err != nil {
+			return err
+		}
+		pending[target] = content
+	}
+	return nil
+}
+
+// readFileRefs adds to merged the variables referenced by its keys ending
+// with the `FileRefSuffix` of the config, for Read. Unlike resolveFileRefs,
+// it ignores the environment, and the targets come from the layer of their
+// reference.
+func (ue *UdotEnv) readFileRefs(merged map[string]entry) error {
+	if ue.Config == nil || ue.Config.FileRefSuffix == "" {
+		return nil
+	}
+	suffix := ue.Config.FileRefSuffix
+
+	for _, k := range sortedKeys(merged) {
+		target, ok := strings.CutSuffix(k, suffix)
+		if !ok || target == "" || (ue.Config.KeyPattern != nil && !ue.Config.KeyPattern.MatchString(target)) {
+			continue
+		}
+		if _, ok := merged[target]; ok {
+			return withSentinel(fmt.Errorf("both %s and %s are defined", target, k), ErrValidation)
+		}
+
+		e := merged[k]
+		content, err := readFileRef(k, target, e.value)
+		if err != nil {
+			return err
+		}
+		merged[target] = entry{value: content, overload: e.overload, source: e.source, literal: true}
+	}
+	return nil
+}
+
+// readFileRef returns the content of the file at path referenced by the
+// variable k, without its trailing newlines.
+func readFileRef(k, target, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s for %s: %w", k, target, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
@@ -0,0 +1,78 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_FileRefs(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "db_password")
+	_ = os.WriteFile(secret, []byte("s3cret\n"), 0o644)
+	token := filepath.Join(dir, "api_token")
+	_ = os.WriteFile(token, []byte("tok"), 0o644)
+	path := filepath.Join(dir, ".env")
+	_ = os.WriteFile(path, []byte("REF_DB_PASSWORD_FILE="+secret+"\n"), 0o644)
+
+	t.Setenv("REF_API_TOKEN_FILE", token)
+	t.Setenv("REF_KEPT_FILE", token)
+	t.Setenv("REF_KEPT", "kept")
+	t.Setenv("OTHER_REF_FILE", token)
+	// not declared, so neither read nor checked
+	t.Setenv("REF_AMBIENT_FILE", filepath.Join(dir, "missing"))
+	for _, k := range []string{"REF_DB_PASSWORD_FILE", "REF_DB_PASSWORD", "REF_API_TOKEN"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			FileRefSuffix: "_FILE",
+			KeyPattern:    regexp.MustCompile(`^REF_`),
+			KnownKeys:     []string{"REF_API_TOKEN", "REF_KEPT"},
+		},
+		EnvParam: stringSlice{path},
+	}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "s3cret", os.Getenv("REF_DB_PASSWORD"))
+	assert.Equal(t, secret, os.Getenv("REF_DB_PASSWORD_FILE"))
+	assert.Equal(t, "tok", os.Getenv("REF_API_TOKEN"))
+	assert.Equal(t, "kept", os.Getenv("REF_KEPT"))
+	_, ok := os.LookupEnv("OTHER_REF")
+	assert.False(t, ok)
+	_, ok = os.LookupEnv("REF_AMBIENT")
+	assert.False(t, ok)
+
+	// the targets follow the referenced files on reload
+	_ = os.WriteFile(secret, []byte("rotated\n"), 0o644)
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "rotated", os.Getenv("REF_DB_PASSWORD"))
+}
+
+func TestLoad_FileRefErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	missing := filepath.Join(dir, "missing")
+	udotEnv := &UdotEnv{
+		Config:   &Config{FileRefSuffix: "_FILE", KeyPattern: regexp.MustCompile(`^REF_`)},
+		EnvParam: stringSlice{path},
+	}
+
+	_ = os.WriteFile(path, []byte("REF_ERR_FILE="+missing+"\n"), 0o644)
+	assert.ErrorContains(t, udotEnv.Load(), "reading REF_ERR_FILE for REF_ERR: open "+missing)
+	_, ok := os.LookupEnv("REF_ERR_FILE")
+	assert.False(t, ok)
+
+	_ = os.WriteFile(path, []byte("REF_ERR=plain\nREF_ERR_FILE="+missing+"\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "both REF_ERR and REF_ERR_FILE are defined")
+
+	// disabled without a suffix
+	udotEnv.Config.FileRefSuffix = ""
+	defer os.Unsetenv("REF_ERR")
+	defer os.Unsetenv("REF_ERR_FILE")
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "plain", os.Getenv("REF_ERR"))
+}
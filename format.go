@@ -0,0 +1,228 @@
+package udotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the format of an env file.
+type Format int
+
+const (
+	// FormatAuto detects the format from the extension of the file, falling
+	// back to FormatDotenv.
+	FormatAuto Format = iota
+	// FormatDotenv is the `KEY=value` syntax understood by godotenv.
+	FormatDotenv
+	// FormatJSON is a JSON object. Nested objects are flattened.
+	FormatJSON
+	// FormatYAML is a YAML mapping. Nested mappings are flattened.
+	FormatYAML
+	// FormatTOML is a TOML document. Tables are flattened.
+	FormatTOML
+)
+
+const defaultKeySeparator = "_"
+
+var formatExts = map[string]Format{
+	".json": FormatJSON,
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".toml": FormatTOML,
+}
+
+// format returns the format of the file at path: the `Format` of the config
+// if set, or the one matching its extension. The `.gz` and `.age` extensions
+// are ignored, so that `config.json.gz` is a JSON file.
+func (ue *UdotEnv) format(path string) Format {
+	if ue.Config != nil && ue.Config.Format != FormatAuto {
+		return ue.Config.Format
+	}
+
+	for {
+		ext := filepath.Ext(path)
+		if ext != ".gz" && ext != ageExt {
+			if f, ok := formatExts[strings.ToLower(ext)]; ok {
+				return f
+			}
+			return FormatDotenv
+		}
+		path = strings.TrimSuffix(path, ext)
+	}
+}
+
+// parseStructured parses src in format f, which is not FormatDotenv.
+func (ue *UdotEnv) parseStructured(f Format, src []byte) (map[string]string, error) {
+	vars := make(map[string]string)
+	switch f {
+	case FormatJSON:
+		var fields map[string]any
+		dec := json.NewDecoder(bytes.NewReader(src))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return nil, withSentinel(fmt.Errorf("parsing JSON: %w", err), ErrParse)
+		}
+		if dec.More() {
+			return nil, withSentinel(errors.New("parsing JSON: unexpected content after the object"), ErrParse)
+		}
+		if err := ue.flatten(vars, "", fields); err != nil {
+			return nil, withSentinel(fmt.Errorf("parsing JSON: %w", err), ErrParse)
+		}
+	case FormatYAML:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(src, &doc); err != nil {
+			return nil, withSentinel(fmt.Errorf("parsing YAML: %w", err), ErrParse)
+		}
+		if len(doc.Content) > 0 {
+			if err := ue.flattenYAML(vars, "", doc.Content[0]); err != nil {
+				return nil, withSentinel(fmt.Errorf("parsing YAML: %w", err), ErrParse)
+			}
+		}
+	case FormatTOML:
+		var fields map[string]any
+		if err := toml.Unmarshal(src, &fields); err != nil {
+			return nil, withSentinel(fmt.Errorf("parsing TOML: %w", err), ErrParse)
+		}
+		if err := ue.flatten(vars, "", fields); err != nil {
+			return nil, withSentinel(fmt.Errorf("parsing TOML: %w", err), ErrParse)
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %d", f)
+	}
+
+	if ue.expanding() {
+		vars = escapeDollars(vars)
+	}
+	return vars, nil
+}
+
+// flatten adds the fields of a decoded JSON object or TOML document to vars,
+// converting their names with EnvKey and joining the names of nested objects
+// with the `KeySeparator` of the config. Dates and times are added in their
+// RFC 3339 form, arrays and other values that are not strings as JSON, and
+// null values as empty strings. Fields that map to the same variable, e.g.
+// "A_B" and "B" nested in "A", are an error, since the order of the fields is
+// lost in decoding.
+func (ue *UdotEnv) flatten(vars map[string]string, prefix string, fields map[string]any) error {
+	for name, v := range fields {
+		key := ue.joinKey(prefix, name)
+		if _, ok := vars[key]; ok {
+			return fmt.Errorf("%s is set by more than one field", key)
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			if err := ue.flatten(vars, key, v); err != nil {
+				return err
+			}
+		case string:
+			vars[key] = v
+		case nil:
+			vars[key] = ""
+		case time.Time:
+			vars[key] = formatTime(v)
+		default:
+			b, _ := json.Marshal(v)
+			vars[key] = string(b)
+		}
+	}
+	return nil
+}
+
+// flattenYAML adds the entries of the YAML mapping node to vars, the same way
+// flatten does. Scalars are added as written, and sequences as JSON.
+func (ue *UdotEnv) flattenYAML(vars map[string]string, prefix string, node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, v := node.Content[i], node.Content[i+1]
+		if v.Kind == yaml.AliasNode {
+			v = v.Alias
+		}
+		if name.Tag == "!!merge" {
+			if err := ue.flattenYAML(vars, prefix, v); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := ue.joinKey(prefix, name.Value)
+		switch {
+		case v.Kind == yaml.MappingNode:
+			if err := ue.flattenYAML(vars, key, v); err != nil {
+				return err
+			}
+		case v.Kind == yaml.SequenceNode:
+			var items []any
+			if err := v.Decode(&items); err != nil {
+				return err
+			}
+			b, err := json.Marshal(items)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", v.Line, err)
+			}
+			vars[key] = string(b)
+		case v.Tag == "!!null":
+			vars[key] = ""
+		default:
+			vars[key] = v.Value
+		}
+	}
+	return nil
+}
+
+// formatTime formats t in RFC 3339, without the time or the offset for the
+// local dates and times of TOML, which the decoder marks with dedicated
+// locations.
+func formatTime(t time.Time) string {
+	switch t.Location().String() {
+	case "date-local":
+		return t.Format(time.DateOnly)
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// joinKey returns the variable name of the field name nested under prefix.
+func (ue *UdotEnv) joinKey(prefix, name string) string {
+	if prefix == "" {
+		return EnvKey(name)
+	}
+
+	sep := defaultKeySeparator
+	if ue.Config != nil && ue.Config.KeySeparator != "" {
+		sep = ue.Config.KeySeparator
+	}
+	return prefix + sep + EnvKey(name)
+}
+
+// EnvKey converts a name, such as the field of a structured env file or the
+// name of a secret in a secret manager, to a variable name: letters are
+// uppercased, and the characters that may not appear in a name are replaced
+// by underscores, so `db/host` becomes `DB_HOST`.
+func EnvKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
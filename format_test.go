@@ -0,0 +1,185 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_JSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	_ = os.WriteFile(path, []byte(`{
+		"JSON_NAME": "api",
+		"json_port": 8080,
+		"json_db": {"host": "db", "read-only": true, "replicas": null},
+		"JSON_TAGS": ["a", "b"]
+	}`), 0o644)
+	keys := []string{"JSON_NAME", "JSON_PORT", "JSON_DB_HOST", "JSON_DB_READ_ONLY", "JSON_DB_REPLICAS", "JSON_TAGS"}
+	for _, k := range keys {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "api", os.Getenv("JSON_NAME"))
+	assert.Equal(t, "8080", os.Getenv("JSON_PORT"))
+	assert.Equal(t, "db", os.Getenv("JSON_DB_HOST"))
+	assert.Equal(t, "true", os.Getenv("JSON_DB_READ_ONLY"))
+	assert.Equal(t, "", os.Getenv("JSON_DB_REPLICAS"))
+	assert.Equal(t, `["a","b"]`, os.Getenv("JSON_TAGS"))
+}
+
+func TestLoad_FlattenCollision(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "config.json")
+	_ = os.WriteFile(jsonPath, []byte(`{"A_B": "flat", "A": {"B": "nested"}}`), 0o644)
+	tomlPath := filepath.Join(dir, "config.toml")
+	_ = os.WriteFile(tomlPath, []byte("a_b = \"flat\"\n[a]\nb = \"nested\"\n"), 0o644)
+
+	for path, format := range map[string]string{jsonPath: "JSON", tomlPath: "TOML"} {
+		udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+		err := udotEnv.Load()
+		assert.EqualError(t, err, "error loading file '"+path+"': parsing "+format+": A_B is set by more than one field")
+		assert.ErrorIs(t, err, ErrParse)
+	}
+	assert.Empty(t, os.Getenv("A_B"))
+}
+
+func TestLoad_FormatOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	_ = os.WriteFile(path, []byte(`{"FORMAT_KEY": "$literal"}`), 0o644)
+	defer os.Unsetenv("FORMAT_KEY")
+
+	udotEnv := &UdotEnv{Config: &Config{Format: FormatJSON, Expand: true}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "$literal", os.Getenv("FORMAT_KEY"))
+
+	_ = os.WriteFile(path, []byte(`{"FORMAT_KEY": 1} {}`), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+path+"': parsing JSON: unexpected content after the object")
+
+	_ = os.WriteFile(path, []byte(`["FORMAT_KEY"]`), 0o644)
+	err := udotEnv.Load()
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "error loading file '"+path+"': parsing JSON: "))
+}
+
+func TestFormat(t *testing.T) {
+	udotEnv := &UdotEnv{}
+	assert.Equal(t, FormatJSON, udotEnv.format("config.json"))
+	assert.Equal(t, FormatJSON, udotEnv.format("config.JSON.gz"))
+	assert.Equal(t, FormatJSON, udotEnv.format("config.json.age"))
+	assert.Equal(t, FormatYAML, udotEnv.format("values.yaml"))
+	assert.Equal(t, FormatYAML, udotEnv.format("values.yml"))
+	assert.Equal(t, FormatTOML, udotEnv.format("config.toml"))
+	assert.Equal(t, FormatDotenv, udotEnv.format(".env"))
+	assert.Equal(t, FormatDotenv, udotEnv.format(".env.gz"))
+	assert.Equal(t, FormatDotenv, udotEnv.format(""))
+}
+
+func TestLoad_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.yaml")
+	_ = os.WriteFile(path, []byte(`
+defaults: &defaults
+  timeout: 30s
+yaml:
+  image:
+    repository: nginx
+    tag: "1.25"
+  replicaCount: 2
+  mode: 0755
+  debug: false
+  empty: ~
+  hosts:
+    - a.example.com
+    - b.example.com
+  service:
+    <<: *defaults
+    port: 80
+`), 0o644)
+	keys := []string{"DEFAULTS.TIMEOUT", "YAML.IMAGE.REPOSITORY", "YAML.IMAGE.TAG", "YAML.REPLICACOUNT", "YAML.MODE",
+		"YAML.DEBUG", "YAML.EMPTY", "YAML.HOSTS", "YAML.SERVICE.TIMEOUT", "YAML.SERVICE.PORT"}
+	for _, k := range keys {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{KeySeparator: "."}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "30s", os.Getenv("DEFAULTS.TIMEOUT"))
+	assert.Equal(t, "nginx", os.Getenv("YAML.IMAGE.REPOSITORY"))
+	assert.Equal(t, "1.25", os.Getenv("YAML.IMAGE.TAG"))
+	assert.Equal(t, "2", os.Getenv("YAML.REPLICACOUNT"))
+	assert.Equal(t, "0755", os.Getenv("YAML.MODE"))
+	assert.Equal(t, "false", os.Getenv("YAML.DEBUG"))
+	assert.Equal(t, "", os.Getenv("YAML.EMPTY"))
+	assert.Equal(t, `["a.example.com","b.example.com"]`, os.Getenv("YAML.HOSTS"))
+	assert.Equal(t, "30s", os.Getenv("YAML.SERVICE.TIMEOUT"))
+	assert.Equal(t, "80", os.Getenv("YAML.SERVICE.PORT"))
+}
+
+func TestLoad_YAMLErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.yml")
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+
+	_ = os.WriteFile(path, []byte("- a\n- b\n"), 0o644)
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+path+"': parsing YAML: line 1: expected a mapping")
+
+	_ = os.WriteFile(path, []byte("key: [a\n"), 0o644)
+	err := udotEnv.Load()
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "error loading file '"+path+"': parsing YAML: yaml: "))
+
+	_ = os.WriteFile(path, nil, 0o644)
+	assert.NoError(t, udotEnv.Load())
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	_ = os.WriteFile(path, []byte(`
+toml_title = "app"
+toml_ratio = 1.5
+
+[toml_server]
+port = 8080
+enabled = true
+started = 2024-05-01T10:00:00Z
+day = 2024-05-01
+at = 07:30:00
+local = 2024-05-01T07:30:00.5
+ports = [80, 443]
+
+[toml_server.tls]
+cert = "/etc/cert.pem"
+`), 0o644)
+	keys := []string{"TOML_TITLE", "TOML_RATIO", "TOML_SERVER_PORT", "TOML_SERVER_ENABLED", "TOML_SERVER_STARTED",
+		"TOML_SERVER_DAY", "TOML_SERVER_AT", "TOML_SERVER_LOCAL", "TOML_SERVER_PORTS", "TOML_SERVER_TLS_CERT"}
+	for _, k := range keys {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "app", os.Getenv("TOML_TITLE"))
+	assert.Equal(t, "1.5", os.Getenv("TOML_RATIO"))
+	assert.Equal(t, "8080", os.Getenv("TOML_SERVER_PORT"))
+	assert.Equal(t, "true", os.Getenv("TOML_SERVER_ENABLED"))
+	assert.Equal(t, "2024-05-01T10:00:00Z", os.Getenv("TOML_SERVER_STARTED"))
+	assert.Equal(t, "2024-05-01", os.Getenv("TOML_SERVER_DAY"))
+	assert.Equal(t, "07:30:00", os.Getenv("TOML_SERVER_AT"))
+	assert.Equal(t, "2024-05-01T07:30:00.5", os.Getenv("TOML_SERVER_LOCAL"))
+	assert.Equal(t, "[80,443]", os.Getenv("TOML_SERVER_PORTS"))
+	assert.Equal(t, "/etc/cert.pem", os.Getenv("TOML_SERVER_TLS_CERT"))
+
+	_ = os.WriteFile(path, []byte("toml_title = \n"), 0o644)
+	err := udotEnv.Load()
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "error loading file '"+path+"': parsing TOML: "))
+}
+
+func TestEnvKey(t *testing.T) {
+	assert.Equal(t, "DB_HOST", EnvKey("db/host"))
+	assert.Equal(t, "API_KEY_2", EnvKey("api-key.2"))
+}
@@ -0,0 +1,54 @@
+package udotenv
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+)
+
+// LoadFS loads the env files at paths of fsys into the environment, with the
+// same rules as LoadReader. It is meant for defaults embedded in the binary,
+// which the files of a later Load may then overwrite:
+//
+//	//go:embed defaults.env
+//	var defaults embed.FS
+//
+//	err := ue.LoadFS(defaults, "defaults.env")
+//
+// Several paths are ordered by the `Precedence` of the config, like the files
+// passed through the flags. The `#include` directives of the files are
+// resolved in fsys.
+func (ue *UdotEnv) LoadFS(fsys fs.FS, paths ...string) error {
+	ue.mu.Lock()
+	err := ue.loadFS(fsys, paths)
+	ue.mu.Unlock()
+	return ue.fail(err)
+}
+
+func (ue *UdotEnv) loadFS(fsys fs.FS, paths []string) error {
+	paths = slices.Clone(paths)
+	if ue.precedence() == FirstWins {
+		slices.Reverse(paths)
+	}
+
+	layers := make([]layer, 0, len(paths))
+	for _, path := range paths {
+		vars, err := ue.parseFSFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("error loading file '%s': %w", path, err)
+		}
+		layers = append(layers, layer{path: path, vars: vars, overload: ue.Overload()})
+	}
+	return ue.loadLayers(layers)
+}
+
+// parseFSFile reads, decompresses and parses the env file at path of fsys,
+// whose includes are read from fsys as well.
+func (ue *UdotEnv) parseFSFile(fsys fs.FS, path string) (map[string]string, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	defer f.Close()
+	return ue.parseIncluded(path, f, []string{path}, fsys)
+}
@@ -0,0 +1,38 @@
+package udotenv
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"defaults.env": {Data: []byte("FS_A=default\nFS_B=default\nFS_C=default\n")},
+		"extra.env":    {Data: []byte("FS_C=extra\n")},
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("FS_B=file\n"), 0o644)
+	for _, k := range []string{"FS_A", "FS_B", "FS_C"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{Precedence: LastWins}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.LoadFS(fsys, "defaults.env", "extra.env"))
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "default", os.Getenv("FS_A"))
+	assert.Equal(t, "file", os.Getenv("FS_B"))
+	assert.Equal(t, "extra", os.Getenv("FS_C"))
+}
+
+func TestLoadFS_Missing(t *testing.T) {
+	udotEnv := &UdotEnv{Config: &Config{}}
+	err := udotEnv.LoadFS(fstest.MapFS{}, "missing.env")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorContains(t, err, "error loading file 'missing.env'")
+}
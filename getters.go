@@ -0,0 +1,131 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrKeyNotFound is returned by the getters for keys that were not loaded.
+var ErrKeyNotFound = errors.New("key not found")
+
+// lookup returns the value of key as loaded by the last call to Load.
+func (ue *UdotEnv) lookup(key string) (string, error) {
+	ue.mu.RLock()
+	v, ok := ue.vars[key]
+	ue.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%s: %w", key, ErrKeyNotFound)
+	}
+	return v, nil
+}
+
+// loadedVars returns a copy of the variables loaded by the last call to Load.
+func (ue *UdotEnv) loadedVars() map[string]string {
+	ue.mu.RLock()
+	defer ue.mu.RUnlock()
+	return maps.Clone(ue.vars)
+}
+
+// get looks key up and converts its value with parse.
+func get[T any](ue *UdotEnv, key string, parse func(string) (T, error)) (T, error) {
+	v, err := ue.lookup(key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	t, err := parse(v)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("%s: %w", key, ue.maskError(key, v, err))
+	}
+	return t, nil
+}
+
+// getOr is like get, but returns def instead of an error.
+func getOr[T any](ue *UdotEnv, key string, def T, parse func(string) (T, error)) T {
+	t, err := get(ue, key, parse)
+	if err != nil {
+		return def
+	}
+	return t
+}
+
+func parseString(v string) (string, error) {
+	return v, nil
+}
+
+func parseFloat64(v string) (float64, error) {
+	return strconv.ParseFloat(v, 64)
+}
+
+// The getters below read the variables loaded by the last call to Load; keys
+// that were not in the loaded files are reported with ErrKeyNotFound, even if
+// they are set in the environment. The `Or` variants return the given default
+// when the key is missing or its value cannot be converted.
+
+// GetString returns the value of key.
+func (ue *UdotEnv) GetString(key string) (string, error) {
+	return get(ue, key, parseString)
+}
+
+// GetStringOr returns the value of key, or def.
+func (ue *UdotEnv) GetStringOr(key string, def string) string {
+	return getOr(ue, key, def, parseString)
+}
+
+// GetInt returns the value of key as an int.
+func (ue *UdotEnv) GetInt(key string) (int, error) {
+	return get(ue, key, strconv.Atoi)
+}
+
+// GetIntOr returns the value of key as an int, or def.
+func (ue *UdotEnv) GetIntOr(key string, def int) int {
+	return getOr(ue, key, def, strconv.Atoi)
+}
+
+// GetBool returns the value of key as a bool. Besides the values accepted by
+// strconv.ParseBool, "yes", "no", "y", "n", "on" and "off" are accepted in any
+// case.
+func (ue *UdotEnv) GetBool(key string) (bool, error) {
+	return get(ue, key, parseBool)
+}
+
+// GetBoolOr returns the value of key as a bool, or def.
+func (ue *UdotEnv) GetBoolOr(key string, def bool) bool {
+	return getOr(ue, key, def, parseBool)
+}
+
+// GetFloat64 returns the value of key as a float64.
+func (ue *UdotEnv) GetFloat64(key string) (float64, error) {
+	return get(ue, key, parseFloat64)
+}
+
+// GetFloat64Or returns the value of key as a float64, or def.
+func (ue *UdotEnv) GetFloat64Or(key string, def float64) float64 {
+	return getOr(ue, key, def, parseFloat64)
+}
+
+// GetDuration returns the value of key parsed by time.ParseDuration.
+func (ue *UdotEnv) GetDuration(key string) (time.Duration, error) {
+	return get(ue, key, time.ParseDuration)
+}
+
+// GetDurationOr returns the value of key parsed by time.ParseDuration, or def.
+func (ue *UdotEnv) GetDurationOr(key string, def time.Duration) time.Duration {
+	return getOr(ue, key, def, time.ParseDuration)
+}
+
+// GetURL returns the value of key parsed by url.Parse.
+func (ue *UdotEnv) GetURL(key string) (*url.URL, error) {
+	return get(ue, key, url.Parse)
+}
+
+// GetURLOr returns the value of key parsed by url.Parse, or def.
+func (ue *UdotEnv) GetURLOr(key string, def *url.URL) *url.URL {
+	return getOr(ue, key, def, url.Parse)
+}
@@ -0,0 +1,85 @@
+package udotenv
+
+import (
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadedUdotEnv(t *testing.T, content string) *UdotEnv {
+	t.Helper()
+
+	_ = os.WriteFile(".test.env", []byte(content), 0o644)
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".test.env"}, OverloadParam: true}
+	assert.NoError(t, udotEnv.Load())
+
+	doc, err := parseDocument([]byte(content))
+	assert.NoError(t, err)
+	for k := range doc.vars() {
+		t.Cleanup(func() { os.Unsetenv(k) })
+	}
+	return udotEnv
+}
+
+func TestGetters(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "GET_STRING=text\n"+
+		"GET_INT=42\n"+
+		"GET_BOOL=yes\n"+
+		"GET_FLOAT=1.5\n"+
+		"GET_DURATION=1m30s\n"+
+		"GET_URL=https://example.com/path\n")
+
+	s, err := udotEnv.GetString("GET_STRING")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", s)
+
+	i, err := udotEnv.GetInt("GET_INT")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, i)
+
+	b, err := udotEnv.GetBool("GET_BOOL")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	f, err := udotEnv.GetFloat64("GET_FLOAT")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+
+	d, err := udotEnv.GetDuration("GET_DURATION")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	u, err := udotEnv.GetURL("GET_URL")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", u.Host)
+}
+
+func TestGetters_Errors(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "GET_BAD_INT=forty\n")
+
+	os.Setenv("GET_ENV_ONLY", "1")
+	defer os.Unsetenv("GET_ENV_ONLY")
+
+	_, err := udotEnv.GetInt("GET_ENV_ONLY")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = udotEnv.GetInt("GET_BAD_INT")
+	assert.ErrorContains(t, err, "GET_BAD_INT: ")
+}
+
+func TestGetters_Defaults(t *testing.T) {
+	udotEnv := loadedUdotEnv(t, "GET_DEF_INT=forty\n")
+	def, _ := url.Parse("http://localhost")
+
+	assert.Equal(t, "def", udotEnv.GetStringOr("GET_DEF_MISSING", "def"))
+	assert.Equal(t, 7, udotEnv.GetIntOr("GET_DEF_INT", 7))
+	assert.True(t, udotEnv.GetBoolOr("GET_DEF_MISSING", true))
+	assert.Equal(t, 2.5, udotEnv.GetFloat64Or("GET_DEF_MISSING", 2.5))
+	assert.Equal(t, time.Second, udotEnv.GetDurationOr("GET_DEF_MISSING", time.Second))
+	assert.Equal(t, def, udotEnv.GetURLOr("GET_DEF_MISSING", def))
+}
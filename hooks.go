@@ -0,0 +1,36 @@
+package udotenv
+
+import "slices"
+
+// beforeSet passes the variables of pending to the `OnBeforeSet` hook of the
+// config, in key order, replacing their values with the returned ones and
+// dropping the vetoed variables from pending and merged.
+func (ue *UdotEnv) beforeSet(merged map[string]entry, pending map[string]string) {
+	if ue.Config == nil || ue.Config.OnBeforeSet == nil {
+		return
+	}
+
+	for _, k := range sortedKeys(pending) {
+		v, ok := ue.Config.OnBeforeSet(k, pending[k])
+		if !ok {
+			ue.logger().Debug("variable vetoed", "key", k)
+			delete(pending, k)
+			delete(merged, k)
+			continue
+		}
+		pending[k] = v
+	}
+}
+
+// afterLoad calls the `OnAfterLoad` hook of the config with the variables set
+// by the load that just ended, or with its error.
+func (ue *UdotEnv) afterLoad(err error) {
+	if ue.Config == nil || ue.Config.OnAfterLoad == nil {
+		return
+	}
+	if err != nil {
+		ue.Config.OnAfterLoad(nil, err)
+		return
+	}
+	ue.Config.OnAfterLoad(slices.Clone(ue.loaded), nil)
+}
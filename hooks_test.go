@@ -0,0 +1,69 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Hooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("HOOK_NAME=api\nHOOK_VETOED=x\nHOOK_PORT=80\n"), 0o644)
+	for _, k := range []string{"HOOK_NAME", "HOOK_VETOED", "HOOK_PORT"} {
+		defer os.Unsetenv(k)
+	}
+
+	var seen []string
+	var loads [][]LoadedKey
+	config := &Config{
+		Schema: Schema{"HOOK_PORT": {Type: KindInt}},
+		OnBeforeSet: func(key, value string) (string, bool) {
+			seen = append(seen, key)
+			if key == "HOOK_VETOED" {
+				return "", false
+			}
+			return strings.ToUpper(value), true
+		},
+		OnAfterLoad: func(loaded []LoadedKey, err error) {
+			assert.NoError(t, err)
+			loads = append(loads, loaded)
+		},
+	}
+	udotEnv := &UdotEnv{Config: config, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, []string{"HOOK_NAME", "HOOK_PORT", "HOOK_VETOED"}, seen)
+	assert.Equal(t, "API", os.Getenv("HOOK_NAME"))
+	_, ok := os.LookupEnv("HOOK_VETOED")
+	assert.False(t, ok)
+	_, err := udotEnv.GetString("HOOK_VETOED")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Equal(t, [][]LoadedKey{{
+		{Key: "HOOK_NAME", Source: path},
+		{Key: "HOOK_PORT", Source: path},
+	}}, loads)
+
+	assert.NoError(t, udotEnv.LoadString("HOOK_EXTRA=1\n"))
+	defer os.Unsetenv("HOOK_EXTRA")
+	assert.Len(t, loads, 2)
+}
+
+func TestLoad_HooksError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("HOOK_ERR_PORT=80\n"), 0o644)
+
+	var loadErr error
+	config := &Config{
+		Schema:      Schema{"HOOK_ERR_PORT": {Type: KindInt}},
+		OnBeforeSet: func(key, value string) (string, bool) { return "eighty", true },
+		OnAfterLoad: func(_ []LoadedKey, err error) { loadErr = err },
+	}
+	err := (&UdotEnv{Config: config, EnvParam: stringSlice{path}}).Load()
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, err, loadErr)
+	_, ok := os.LookupEnv("HOOK_ERR_PORT")
+	assert.False(t, ok)
+}
@@ -0,0 +1,117 @@
+package udotenv
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	defaultHTTPMaxSize = 10 << 20
+)
+
+// HTTPConfig configures how env files passed as http:// or https:// URLs are
+// fetched.
+type HTTPConfig struct {
+	// Timeout bounds each request. It defaults to 30 seconds.
+	Timeout time.Duration
+	// Headers are added to each request.
+	Headers map[string]string
+	// BearerToken, when set, is sent in the Authorization header.
+	BearerToken string
+	// TLSConfig is used by the default client, e.g. to trust an internal CA
+	// or to present a client certificate.
+	TLSConfig *tls.Config
+	// Client replaces the default client. TLSConfig is then ignored.
+	Client *http.Client
+	// MaxSize bounds the size of a fetched file, in bytes. It defaults to
+	// 10 MiB.
+	MaxSize int64
+}
+
+// isURL reports whether path is the URL of a remote env file.
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// fetch downloads and parses the env file at url.
+func (ue *UdotEnv) fetch(url string) (map[string]string, error) {
+	var cfg HTTPConfig
+	if ue.Config != nil && ue.Config.HTTP != nil {
+		cfg = *ue.Config.HTTP
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultHTTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ue.loadContext(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if cfg.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.BearerToken)
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = ue.defaultClient(cfg.TLSConfig)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, withSentinel(fmt.Errorf("unexpected status %s", resp.Status), ErrFileNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultHTTPMaxSize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", maxSize)
+	}
+	return ue.parse(req.URL.Path, bytes.NewReader(body))
+}
+
+// defaultClient returns the client that fetches remote files when the config
+// sets none. It is built once per UdotEnv, and again only when tlsConfig
+// changes, so that its connections are reused across loads.
+func (ue *UdotEnv) defaultClient(tlsConfig *tls.Config) *http.Client {
+	ue.httpMu.Lock()
+	defer ue.httpMu.Unlock()
+	if ue.httpClient == nil || ue.httpTLS != tlsConfig {
+		if ue.httpClient != nil {
+			ue.httpClient.CloseIdleConnections()
+		}
+		ue.httpClient = &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}}
+		ue.httpTLS = tlsConfig
+	}
+	return ue.httpClient
+}
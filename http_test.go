@@ -0,0 +1,92 @@
+package udotenv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_HTTP(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("X-App") != "test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("HTTP_A=remote\n"))
+	}))
+	defer server.Close()
+	defer os.Unsetenv("HTTP_A")
+
+	udotEnv := &UdotEnv{
+		Config: &Config{HTTP: &HTTPConfig{
+			Headers:     map[string]string{"X-App": "test"},
+			BearerToken: "token",
+			Client:      server.Client(),
+		}},
+		EnvParam: stringSlice{server.URL + "/app.env"},
+	}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "remote", os.Getenv("HTTP_A"))
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestLoad_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{server.URL + "/app.env"}}
+	assert.EqualError(t, udotEnv.Load(),
+		"error loading file '"+server.URL+"/app.env': unexpected status 404 Not Found")
+}
+
+func TestLoad_HTTPTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{HTTP: &HTTPConfig{Timeout: 50 * time.Millisecond}},
+		EnvParam: stringSlice{server.URL + "/app.env"},
+	}
+	assert.ErrorContains(t, udotEnv.Load(), "deadline exceeded")
+}
+
+func TestLoad_HTTPMaxSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("HTTP_BIG=0123456789\n"))
+	}))
+	defer server.Close()
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{HTTP: &HTTPConfig{MaxSize: 10}},
+		EnvParam: stringSlice{server.URL + "/app.env"},
+	}
+	assert.EqualError(t, udotEnv.Load(),
+		"error loading file '"+server.URL+"/app.env': file exceeds 10 bytes")
+}
+
+func TestLoad_HTTPDefaultClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("HTTP_B=remote\n"))
+	}))
+	defer server.Close()
+	defer os.Unsetenv("HTTP_B")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{server.URL + "/app.env"}}
+	assert.NoError(t, udotEnv.Load())
+	client := udotEnv.httpClient
+	assert.NotNil(t, client)
+	assert.NoError(t, udotEnv.Load())
+	assert.Same(t, client, udotEnv.httpClient)
+	assert.Equal(t, "remote", os.Getenv("HTTP_B"))
+}
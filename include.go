@@ -0,0 +1,154 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const defaultMaxIncludeDepth = 8
+
+// docVars returns the variables defined by doc, the content of the file at
+// path, along with the ones of the files it includes. An included file is
+// read where its directive appears, so the variables defined after the
+// directive override the included ones. The keys defined several times in doc
+// are handled according to the `DuplicateKeys` policy of the config. The
+// included files are read from fsys, or from the host if it is nil.
+func (ue *UdotEnv) docVars(doc *document, file string, stack []string, fsys fs.FS) (map[string]string, error) {
+	raw := ue.expanding()
+	compose := ue.dialect() == DialectCompose && !raw
+	vars := make(map[string]string, len(doc.statements))
+	defined := make(map[string]int, len(doc.statements))
+	includes := doc.includes
+	for _, st := range doc.statements {
+		for len(includes) > 0 && includes[0].line < st.line {
+			if err := ue.include(vars, includes[0], file, stack, fsys); err != nil {
+				return nil, err
+			}
+			includes = includes[1:]
+		}
+		skip, err := ue.duplicate(doc, file, st, defined)
+		if err != nil {
+			return nil, err
+		} else if skip {
+			continue
+		}
+		if compose {
+			if err := assignCompose(vars, st); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		assign(vars, st, raw)
+	}
+
+	for _, inc := range includes {
+		if err := ue.include(vars, inc, file, stack, fsys); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// include adds the variables of the file included by inc to vars. Relative
+// paths are resolved from the directory of the including file. The files of
+// fsys, e.g. given to LoadFS, only include other files of fsys, and only the
+// local files may include host files: content read from a reader or fetched
+// from a URL or a registered scheme may not include anything. During Load,
+// the included file is stamped before it is read, so that Changed and Watch
+// see its changes.
+func (ue *UdotEnv) include(vars map[string]string, inc include, file string, stack []string, fsys fs.FS) error {
+	if fsys != nil {
+		return ue.includeFS(vars, inc, file, stack, fsys)
+	}
+	if file == "" || isRemote(file) {
+		return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, errors.New("includes are only allowed in files"))
+	}
+
+	target := inc.path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(file), target)
+	}
+
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, err)
+	}
+	if err := ue.checkInclude(inc, stack, abs); err != nil {
+		return err
+	}
+
+	if ue.includeStamps != nil {
+		stamp, err := stampFile(target)
+		if err != nil {
+			return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, err)
+		}
+		ue.includeStamps[target] = stamp
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, notFound(err))
+	}
+	defer f.Close()
+	return ue.addIncluded(vars, inc, target, f, append(slices.Clone(stack), abs), nil)
+}
+
+// includeFS is include for the file of fsys at file, whose includes are
+// resolved in fsys with slash-separated paths.
+func (ue *UdotEnv) includeFS(vars map[string]string, inc include, file string, stack []string, fsys fs.FS) error {
+	target := path.Join(path.Dir(file), inc.path)
+	if !fs.ValidPath(target) || path.IsAbs(inc.path) {
+		return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, errors.New("path outside of the file system"))
+	}
+	if err := ue.checkInclude(inc, stack, target); err != nil {
+		return err
+	}
+
+	f, err := fsys.Open(target)
+	if err != nil {
+		return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, notFound(err))
+	}
+	defer f.Close()
+	return ue.addIncluded(vars, inc, target, f, append(slices.Clone(stack), target), fsys)
+}
+
+// checkInclude reports an include cycle, the file at key being already in
+// stack, or includes nested deeper than the `MaxIncludeDepth` of the config.
+func (ue *UdotEnv) checkInclude(inc include, stack []string, key string) error {
+	if slices.Contains(stack, key) {
+		cycle := append(slices.Clone(stack[slices.Index(stack, key):]), key)
+		return fmt.Errorf("line %d: include cycle: %s", inc.line, strings.Join(cycle, " -> "))
+	}
+	if len(stack) > ue.maxIncludeDepth() {
+		return fmt.Errorf("line %d: includes nested deeper than %d", inc.line, ue.maxIncludeDepth())
+	}
+	return nil
+}
+
+// addIncluded parses the included file at target, read from r, and adds its
+// variables to vars.
+func (ue *UdotEnv) addIncluded(vars map[string]string, inc include, target string, r io.Reader, stack []string, fsys fs.FS) error {
+	included, err := ue.parseIncluded(target, r, stack, fsys)
+	if err != nil {
+		return fmt.Errorf("line %d: including %s: %w", inc.line, inc.path, err)
+	}
+	maps.Copy(vars, included)
+	return nil
+}
+
+// maxIncludeDepth returns the `MaxIncludeDepth` of the config, or its
+// default.
+func (ue *UdotEnv) maxIncludeDepth() int {
+	if ue.Config != nil && ue.Config.MaxIncludeDepth > 0 {
+		return ue.Config.MaxIncludeDepth
+	}
+	return defaultMaxIncludeDepth
+}
@@ -0,0 +1,129 @@
+package udotenv
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Include(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.Mkdir(filepath.Join(dir, "shared"), 0o755)
+	_ = os.WriteFile(filepath.Join(dir, "shared", "common.env"), []byte(
+		"INC_HOST=db\nINC_NAME=common\ndotenv_include=extra.env\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "shared", "extra.env"), []byte("INC_EXTRA=extra\n"), 0o644)
+	path := filepath.Join(dir, ".env")
+	_ = os.WriteFile(path, []byte(
+		"INC_NAME=before\n#include shared/common.env\nINC_URL=postgres://$INC_HOST\nINC_HOST=local\n"), 0o644)
+	for _, k := range []string{"INC_HOST", "INC_NAME", "INC_EXTRA", "INC_URL"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "local", os.Getenv("INC_HOST"))
+	assert.Equal(t, "common", os.Getenv("INC_NAME"))
+	assert.Equal(t, "extra", os.Getenv("INC_EXTRA"))
+	assert.Equal(t, "postgres://db", os.Getenv("INC_URL"))
+}
+
+func TestChanged_Include(t *testing.T) {
+	dir := t.TempDir()
+	frag := filepath.Join(dir, "frag.env")
+	_ = os.WriteFile(frag, []byte("INC_FRAG=one\n"), 0o644)
+	path := filepath.Join(dir, ".env")
+	_ = os.WriteFile(path, []byte("#include frag.env\n"), 0o644)
+	defer os.Unsetenv("INC_FRAG")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	_ = os.WriteFile(frag, []byte("INC_FRAG=three\n"), 0o644)
+	changed, err = udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestLoad_IncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.env")
+	b := filepath.Join(dir, "b.env")
+	_ = os.WriteFile(a, []byte("#include b.env\n"), 0o644)
+	_ = os.WriteFile(b, []byte("dotenv_include=a.env\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{a}}
+	err := udotEnv.Load()
+	assert.ErrorContains(t, err, "line 1: including b.env: line 1: include cycle: "+a+" -> "+b+" -> "+a)
+
+	_ = os.WriteFile(a, []byte("#include missing.env\n"), 0o644)
+	assert.ErrorContains(t, udotEnv.Load(), "line 1: including missing.env: open "+filepath.Join(dir, "missing.env"))
+
+	for i := range 3 {
+		name := filepath.Join(dir, "n"+strings.Repeat("x", i)+".env")
+		next := "n" + strings.Repeat("x", i+1) + ".env"
+		_ = os.WriteFile(name, []byte("#include "+next+"\n"), 0o644)
+	}
+	_ = os.WriteFile(filepath.Join(dir, "nxxx.env"), []byte("INC_DEEP=1\n"), 0o644)
+	defer os.Unsetenv("INC_DEEP")
+
+	udotEnv = &UdotEnv{Config: &Config{MaxIncludeDepth: 2}, EnvParam: stringSlice{filepath.Join(dir, "n.env")}}
+	assert.ErrorContains(t, udotEnv.Load(), "includes nested deeper than 2")
+
+	udotEnv.Config.MaxIncludeDepth = 3
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "1", os.Getenv("INC_DEEP"))
+}
+
+func TestLoadFS_Include(t *testing.T) {
+	host := filepath.Join(t.TempDir(), "host.env")
+	_ = os.WriteFile(host, []byte("INC_FS_HOST=host\n"), 0o644)
+	fsys := fstest.MapFS{
+		"conf/app.env":    {Data: []byte("#include shared.env\nINC_FS_APP=app\n")},
+		"conf/shared.env": {Data: []byte("INC_FS_SHARED=shared\n")},
+		"host.env":        {Data: []byte("#include " + host + "\n")},
+		"up.env":          {Data: []byte("#include ../conf/shared.env\n")},
+		"missing.env":     {Data: []byte("#include host-only.env\n")},
+	}
+	_ = os.WriteFile("host-only.env", []byte("INC_FS_HOST=host\n"), 0o644)
+	defer os.Remove("host-only.env")
+	for _, k := range []string{"INC_FS_APP", "INC_FS_SHARED", "INC_FS_HOST"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{}}
+	assert.NoError(t, udotEnv.LoadFS(fsys, "conf/app.env"))
+	assert.Equal(t, "app", os.Getenv("INC_FS_APP"))
+	assert.Equal(t, "shared", os.Getenv("INC_FS_SHARED"))
+
+	assert.ErrorContains(t, udotEnv.LoadFS(fsys, "host.env"), "path outside of the file system")
+	assert.ErrorContains(t, udotEnv.LoadFS(fsys, "up.env"), "path outside of the file system")
+	assert.ErrorIs(t, udotEnv.LoadFS(fsys, "missing.env"), fs.ErrNotExist)
+	assert.Empty(t, os.Getenv("INC_FS_HOST"))
+}
+
+func TestLoadString_Include(t *testing.T) {
+	_ = os.WriteFile("include.env", []byte("INC_READER=host\n"), 0o644)
+	defer os.Remove("include.env")
+	defer os.Unsetenv("INC_READER")
+
+	udotEnv := &UdotEnv{Config: &Config{}}
+	assert.ErrorContains(t, udotEnv.LoadString("#include include.env\n"), "includes are only allowed in files")
+	_, err := ParseReader(strings.NewReader("dotenv_include=include.env\n"))
+	assert.ErrorContains(t, err, "includes are only allowed in files")
+	assert.Empty(t, os.Getenv("INC_READER"))
+}
+
+func TestParse_IncludeDirective(t *testing.T) {
+	doc, err := parseDocument([]byte("#include  \"a b.env\"\n# include c.env\n#included\ndotenv_include=d.env\nK=v\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []include{{path: "a b.env", line: 1}, {path: "d.env", line: 4}}, doc.includes)
+	assert.Equal(t, map[string]string{"K": "v"}, doc.vars())
+}
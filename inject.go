@@ -0,0 +1,59 @@
+package udotenv
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandEnv reads the env files like Read and returns base, a list of
+// "KEY=value" entries such as the one of exec.Cmd.Env, with their variables
+// added. A nil base stands for the environment of the process. The variables
+// already in base are only replaced with overload, and their position is
+// kept; the others are appended, sorted by key. The environment of the
+// process is left untouched.
+func (ue *UdotEnv) CommandEnv(base []string) ([]string, error) {
+	ue.mu.Lock()
+	merged, err := ue.read(nil)
+	ue.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if base == nil {
+		base = os.Environ()
+	}
+	env := make([]string, 0, len(base)+len(merged))
+	seen := make(map[string]bool, len(base))
+	for _, kv := range base {
+		k, _, _ := strings.Cut(kv, "=")
+		if e, ok := merged[k]; ok && e.overload && !seen[k] {
+			kv = k + "=" + e.value
+		}
+		seen[k] = true
+		env = append(env, kv)
+	}
+	for _, k := range sortedKeys(merged) {
+		if !seen[k] {
+			env = append(env, k+"="+merged[k].value)
+		}
+	}
+	return env, nil
+}
+
+// Inject sets the environment of cmd to the one of CommandEnv, so that only
+// the child process gets the variables of the env files:
+//
+//	cmd := exec.Command("./worker")
+//	if err := udotEnv.Inject(cmd); err != nil {
+//	    return err
+//	}
+//	err := cmd.Run()
+func (ue *UdotEnv) Inject(cmd *exec.Cmd) error {
+	env, err := ue.CommandEnv(cmd.Env)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+	return nil
+}
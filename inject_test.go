@@ -0,0 +1,52 @@
+package udotenv
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("INJECT_NEW=file\nINJECT_KEPT=file\nINJECT_B=file\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	env, err := udotEnv.CommandEnv([]string{"INJECT_KEPT=base", "PATH=/bin"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"INJECT_KEPT=base", "PATH=/bin", "INJECT_B=file", "INJECT_NEW=file"}, env)
+
+	udotEnv.OverloadParam = true
+	env, err = udotEnv.CommandEnv([]string{"INJECT_KEPT=base", "PATH=/bin"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"INJECT_KEPT=file", "PATH=/bin", "INJECT_B=file", "INJECT_NEW=file"}, env)
+
+	_, ok := os.LookupEnv("INJECT_NEW")
+	assert.False(t, ok)
+
+	udotEnv.EnvParam = stringSlice{filepath.Join(t.TempDir(), "missing.env")}
+	_, err = udotEnv.CommandEnv(nil)
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestInject(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh commands")
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("INJECT_GREETING=hello\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	cmd := exec.Command("sh", "-c", `echo "$INJECT_GREETING"`)
+	assert.NoError(t, udotEnv.Inject(cmd))
+
+	out, err := cmd.Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(out))
+	_, ok := os.LookupEnv("INJECT_GREETING")
+	assert.False(t, ok)
+}
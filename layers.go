@@ -0,0 +1,265 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const profilePlaceholder = "{profile}"
+
+// Precedence defines which file wins when several env files passed through
+// the flags define the same key.
+type Precedence int
+
+const (
+	// PrecedenceDefault is FirstWins without overload and LastWins with it.
+	PrecedenceDefault Precedence = iota
+	// FirstWins keeps the value from the first file defining the key.
+	FirstWins
+	// LastWins keeps the value from the last file defining the key, so that
+	// later files override earlier ones.
+	LastWins
+)
+
+var defaultProfileOrder = []string{".env", ".env." + profilePlaceholder, ".env.local", ".env." + profilePlaceholder + ".local"}
+
+// layer holds the variables read from one file.
+type layer struct {
+	path     string
+	vars     map[string]string
+	overload bool // whether the variables overwrite the environment
+	// whether the variables come from a Source or a registered scheme rather
+	// than from env content, in which case they are never rendered as
+	// templates
+	literal bool
+}
+
+// entry is a merged variable.
+type entry struct {
+	value    string
+	overload bool
+	source   string // path of the layer defining the variable
+	literal  bool   // see layer
+}
+
+// resolve merges layers, expands the variables if `Expand` is set in the
+// config, and filters them.
+func (ue *UdotEnv) resolve(layers []layer) (map[string]entry, error) {
+	merged := merge(layers)
+	if ue.expanding() {
+		if err := ue.expand(merged); err != nil {
+			return nil, err
+		}
+	}
+	return ue.filter(merged), nil
+}
+
+// merge merges layers given from the lowest to the highest precedence.
+func merge(layers []layer) map[string]entry {
+	merged := make(map[string]entry)
+	for _, l := range layers {
+		for k, v := range l.vars {
+			merged[k] = entry{value: v, overload: l.overload, source: l.path, literal: l.literal}
+		}
+	}
+	return merged
+}
+
+// filePaths returns the paths of all the files fileLayers considers,
+// including the optional ones that do not exist, so that they can be stamped
+// before they are read.
+func (ue *UdotEnv) filePaths() []string {
+	var paths []string
+	if path := ue.defaultsFile(); path != "" {
+		paths = append(paths, path)
+	}
+	paths = append(paths, ue.profileFiles()...)
+	for _, f := range ue.envFiles() {
+		paths = append(paths, f.path)
+	}
+	if os.Getenv(dotenvKeyEnv) != "" {
+		paths = append(paths, ue.dotenvVaultFile())
+	}
+	return paths
+}
+
+// fileLayers reads the env files and returns their layers, from the lowest to
+// the highest precedence.
+//
+// The precedence is: the `DefaultsFile`, the profile files, then the files
+// passed through the env flags followed by the `Sources` of the config,
+// ordered among themselves by the `Precedence` of the config. When
+// DOTENV_KEY is set and the `.env.vault` file exists, the environment it
+// selects from the vault replaces the files passed through the env flags.
+//
+// Every file is read even if some of them fail, and the errors are joined
+// with errors.Join. The layers of the files read successfully are returned
+// along with the error.
+func (ue *UdotEnv) fileLayers() ([]layer, error) {
+	var layers []layer
+	var errs []error
+
+	if path := ue.defaultsFile(); path != "" {
+		l, err := ue.readOptionalLayer(path, false)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			layers = append(layers, l)
+		}
+	}
+
+	for _, path := range ue.profileFiles() {
+		l, err := ue.readOptionalLayer(path, ue.overloads(path))
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			layers = append(layers, l)
+		}
+	}
+
+	var inputs []layer
+	files := ue.envFiles()
+	vault, ok, err := ue.readDotenvVault()
+	if err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		path := ue.dotenvVaultFile()
+		inputs = append(inputs, layer{path: path, vars: vault, overload: ue.overloads(path)})
+	} else {
+		for _, f := range files {
+			if f.glob {
+				if !ue.ignoresMissing(f.param) {
+					errs = append(errs, withSentinel(fmt.Errorf("no file matches '%s'", f.param), ErrFileNotFound))
+				} else {
+					ue.logger().Debug("no env file matches, skipped", "pattern", f.param)
+				}
+				continue
+			}
+			vars, err := ue.readFile(f.path)
+			if errors.Is(err, ErrFileNotFound) && ue.ignoresMissing(f.param) {
+				ue.logger().Debug("missing env file skipped", "path", f.path)
+				continue
+			} else if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ue.logger().Debug("env file read", "path", f.path, "keys", len(vars))
+			_, scheme := lookupScheme(f.path)
+			inputs = append(inputs, layer{path: f.path, vars: vars, overload: ue.overloads(f.param), literal: scheme})
+		}
+	}
+
+	if ue.Config != nil {
+		for i, src := range ue.Config.Sources {
+			vars, err := src.Fetch(ue.loadContext())
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error loading source %d: %w", i, err))
+				continue
+			}
+			if ue.expanding() {
+				vars = escapeDollars(vars)
+			}
+			ue.logger().Debug("source fetched", "source", i, "keys", len(vars))
+			inputs = append(inputs, layer{path: fmt.Sprintf("source %d", i), vars: vars, overload: ue.Overload(), literal: true})
+		}
+	}
+
+	if ue.precedence() == FirstWins {
+		slices.Reverse(inputs)
+	}
+	return append(layers, inputs...), errors.Join(errs...)
+}
+
+// precedence resolves the `Precedence` of the config. By default, the first
+// file defining a key wins without overload and the last one does with
+// overload, which matches the behaviour of godotenv.Load and
+// godotenv.Overload.
+func (ue *UdotEnv) precedence() Precedence {
+	if ue.Config != nil && ue.Config.Precedence != PrecedenceDefault {
+		return ue.Config.Precedence
+	}
+	if ue.Overload() {
+		return LastWins
+	}
+	return FirstWins
+}
+
+// overloads reports whether the variables of the file at path overwrite the
+// environment.
+func (ue *UdotEnv) overloads(path string) bool {
+	if ue.Overload() {
+		return true
+	}
+	if ue.Config == nil {
+		return false
+	}
+	return slices.ContainsFunc(ue.Config.OverloadFiles, func(p string) bool {
+		return filepath.Clean(p) == filepath.Clean(path)
+	})
+}
+
+// ignoresMissing reports whether the file at path is skipped if it does not
+// exist.
+func (ue *UdotEnv) ignoresMissing(path string) bool {
+	if ue.Config == nil {
+		return false
+	}
+	if ue.Config.IgnoreMissing {
+		return true
+	}
+	return slices.ContainsFunc(ue.Config.OptionalFiles, func(p string) bool {
+		return filepath.Clean(p) == filepath.Clean(path)
+	})
+}
+
+// readOptionalLayer reads the file at path into a layer. A missing file
+// yields an empty layer.
+func (ue *UdotEnv) readOptionalLayer(path string, overload bool) (layer, error) {
+	vars, err := ue.readFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		ue.logger().Debug("missing env file skipped", "path", path)
+		err = nil
+	} else if err == nil {
+		ue.logger().Debug("env file read", "path", path, "keys", len(vars))
+	}
+	return layer{path: path, vars: vars, overload: overload}, err
+}
+
+// defaultsFile returns the `DefaultsFile` of the config, if any.
+func (ue *UdotEnv) defaultsFile() string {
+	if ue.Config == nil {
+		return ""
+	}
+	return ue.Config.DefaultsFile
+}
+
+// profileFiles returns the profile files of the config, from the lowest to
+// the highest precedence.
+func (ue *UdotEnv) profileFiles() []string {
+	if ue.Config == nil || ue.Config.ProfileVar == "" {
+		return nil
+	}
+
+	order := ue.Config.ProfileOrder
+	if len(order) == 0 {
+		order = defaultProfileOrder
+	}
+
+	profile := os.Getenv(ue.Config.ProfileVar)
+	files := make([]string, 0, len(order))
+	for _, path := range order {
+		if strings.Contains(path, profilePlaceholder) {
+			if profile == "" {
+				continue
+			}
+			path = strings.ReplaceAll(path, profilePlaceholder, profile)
+		}
+		files = append(files, path)
+	}
+	return files
+}
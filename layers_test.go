@@ -0,0 +1,141 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Profiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile(".env", []byte("PROFILE_A=base\nPROFILE_B=base\nPROFILE_C=base\nPROFILE_D=base\n"), 0o644)
+	_ = os.WriteFile(".env.production", []byte("PROFILE_B=production\nPROFILE_C=production\nPROFILE_D=production\n"), 0o644)
+	_ = os.WriteFile(".env.production.local", []byte("PROFILE_C=production.local\nPROFILE_D=production.local\n"), 0o644)
+	_ = os.WriteFile("flag.env", []byte("PROFILE_D=flag\n"), 0o644)
+	for _, k := range []string{"PROFILE_A", "PROFILE_B", "PROFILE_C", "PROFILE_D"} {
+		defer os.Unsetenv(k)
+	}
+
+	os.Setenv("PROFILE_APP_ENV", "production")
+	defer os.Unsetenv("PROFILE_APP_ENV")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{ProfileVar: "PROFILE_APP_ENV"},
+		EnvParam: stringSlice{"flag.env"},
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "base", os.Getenv("PROFILE_A"))
+	assert.Equal(t, "production", os.Getenv("PROFILE_B"))
+	assert.Equal(t, "production.local", os.Getenv("PROFILE_C"))
+	assert.Equal(t, "flag", os.Getenv("PROFILE_D"))
+}
+
+func TestProfileFiles(t *testing.T) {
+	dir := t.TempDir()
+	udotEnv := &UdotEnv{Config: &Config{
+		ProfileVar:   "PROFILE_FILES_ENV",
+		ProfileOrder: []string{filepath.Join(dir, "base.env"), filepath.Join(dir, "{profile}.env")},
+	}}
+
+	assert.Equal(t, []string{filepath.Join(dir, "base.env")}, udotEnv.profileFiles())
+
+	os.Setenv("PROFILE_FILES_ENV", "test")
+	defer os.Unsetenv("PROFILE_FILES_ENV")
+
+	assert.Equal(t, []string{filepath.Join(dir, "base.env"), filepath.Join(dir, "test.env")}, udotEnv.profileFiles())
+	assert.Nil(t, (&UdotEnv{Config: &Config{}}).profileFiles())
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("base.env", []byte("PRECEDENCE_KEY=base\n"), 0o644)
+	_ = os.WriteFile("override.env", []byte("PRECEDENCE_KEY=override\n"), 0o644)
+	defer os.Unsetenv("PRECEDENCE_KEY")
+
+	for precedence, expected := range map[Precedence]string{
+		PrecedenceDefault: "base",
+		FirstWins:         "base",
+		LastWins:          "override",
+	} {
+		os.Unsetenv("PRECEDENCE_KEY")
+		udotEnv := &UdotEnv{
+			Config:   &Config{Precedence: precedence},
+			EnvParam: stringSlice{"base.env", "override.env"},
+		}
+		assert.NoError(t, udotEnv.Load())
+		assert.Equal(t, expected, os.Getenv("PRECEDENCE_KEY"), precedence)
+	}
+}
+
+func TestLoad_OverloadFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("base.env", []byte("OVERLOAD_FILES_A=base\nOVERLOAD_FILES_B=base\n"), 0o644)
+	_ = os.WriteFile("override.env", []byte("OVERLOAD_FILES_B=override\n"), 0o644)
+	defer os.Unsetenv("OVERLOAD_FILES_A")
+	defer os.Unsetenv("OVERLOAD_FILES_B")
+
+	os.Setenv("OVERLOAD_FILES_A", "env")
+	os.Setenv("OVERLOAD_FILES_B", "env")
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			Precedence:    LastWins,
+			OverloadFiles: []string{"./override.env"},
+		},
+		EnvParam: stringSlice{"base.env", "override.env"},
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "env", os.Getenv("OVERLOAD_FILES_A"))
+	assert.Equal(t, "override", os.Getenv("OVERLOAD_FILES_B"))
+}
+
+func TestLoad_AggregatedErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("good.env", []byte("AGG_GOOD=1\n"), 0o644)
+	_ = os.WriteFile("bad.env", []byte("BAD KEY=1\n"), 0o644)
+	defer os.Unsetenv("AGG_GOOD")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{"bad.env", "good.env", "missing.env"}}
+	err := udotEnv.Load()
+	assert.EqualError(t, err, "error loading file 'bad.env': line 1, column 4: unexpected character ' ' in variable name \"BAD KEY\"\n"+
+		"error loading file 'missing.env': open missing.env: no such file or directory")
+	assert.ErrorIs(t, err, ErrParse)
+	assert.ErrorIs(t, err, ErrFileNotFound)
+	assert.Empty(t, os.Getenv("AGG_GOOD"))
+}
+
+func TestLoad_ContinueOnError(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("good.env", []byte("CONT_GOOD=1\n"), 0o644)
+	defer os.Unsetenv("CONT_GOOD")
+
+	udotEnv := &UdotEnv{Config: &Config{ContinueOnError: true}, EnvParam: stringSlice{"missing.env", "good.env"}}
+	err := udotEnv.Load()
+	assert.ErrorIs(t, err, ErrFileNotFound)
+	assert.Equal(t, "1", os.Getenv("CONT_GOOD"))
+}
+
+func TestLoad_IgnoreMissing(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile(".env", []byte("MISSING_A=1\n"), 0o644)
+	defer os.Unsetenv("MISSING_A")
+
+	udotEnv := &UdotEnv{Config: &Config{OptionalFiles: []string{"./.env.local"}}, EnvParam: stringSlice{".env", ".env.local"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "1", os.Getenv("MISSING_A"))
+
+	udotEnv = &UdotEnv{Config: &Config{OptionalFiles: []string{".env.local"}}, EnvParam: stringSlice{".env", ".env.required"}}
+	assert.ErrorIs(t, udotEnv.Load(), ErrFileNotFound)
+
+	udotEnv = &UdotEnv{Config: &Config{IgnoreMissing: true}, EnvParam: stringSlice{".env.required", ".env"}}
+	assert.NoError(t, udotEnv.Load())
+
+	_ = os.WriteFile(".env.required", []byte("MISSING_A=2\n"), 0o644)
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
@@ -0,0 +1,117 @@
+package udotenv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// portableKeyRegex matches the variable names that every shell accepts.
+var portableKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Issue is a problem found in an env file by Lint.
+type Issue struct {
+	Line    int    // 1-based line of the problem
+	Rule    string // short name of the check, e.g. "duplicate-key"
+	Message string
+}
+
+// String returns the issue as "line N: message (rule)".
+func (i Issue) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", i.Line, i.Message, i.Rule)
+}
+
+// Lint checks the env file at path for likely mistakes, and returns the
+// issues found, ordered by line. The rules are:
+//   - bom: the file starts with a UTF-8 byte order mark;
+//   - crlf: the line ends with CRLF;
+//   - trailing-whitespace: the line ends with spaces or tabs;
+//   - syntax: the line cannot be parsed;
+//   - invalid-key: the key is not a portable variable name;
+//   - duplicate-key: the key is already defined earlier in the file;
+//   - unquoted-hash: an unquoted value is followed by what is parsed as a
+//     comment, ` #` or a tab and `#`, directly followed by text, as in
+//     `PASS=abc #def`, which loads "abc". Comments starting with `# ` are
+//     taken to be meant as such, and a `#` inside a value, as in `x#y`, is
+//     kept by Load.
+//
+// Unlike Load, Lint goes on after a line that cannot be parsed. An error is
+// only returned if the file cannot be read.
+func Lint(path string) ([]Issue, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	if rest, ok := bytes.CutPrefix(src, utf8BOM); ok {
+		issues = append(issues, Issue{Line: 1, Rule: "bom", Message: "file starts with a UTF-8 byte order mark"})
+		src = rest
+	}
+
+	doc := &document{lines: strings.Split(string(src), "\n")}
+	for i, line := range doc.lines {
+		if trimmed, ok := strings.CutSuffix(line, "\r"); ok {
+			issues = append(issues, Issue{Line: i + 1, Rule: "crlf", Message: "line ends with CRLF"})
+			doc.lines[i] = trimmed
+		}
+		if strings.TrimRight(doc.lines[i], " \t") != doc.lines[i] {
+			issues = append(issues, Issue{Line: i + 1, Rule: "trailing-whitespace", Message: "line ends with whitespace"})
+		}
+	}
+
+	defined := make(map[string]int)
+	for i := 0; i < len(doc.lines); i++ {
+		line := doc.lines[i]
+		start := indexNonSpace(line, 0)
+		if start == len(line) || line[start] == charComment {
+			continue
+		}
+
+		st, err := doc.parseStatement(i, start)
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			issues = append(issues, Issue{Line: pe.Line, Rule: "syntax", Message: pe.Msg})
+			continue
+		}
+		i = st.endLine - 1
+
+		if !portableKeyRegex.MatchString(st.key) {
+			issues = append(issues, Issue{Line: st.line, Rule: "invalid-key",
+				Message: fmt.Sprintf("%s is not a portable variable name", st.key)})
+		}
+		if first, ok := defined[st.key]; ok {
+			issues = append(issues, Issue{Line: st.line, Rule: "duplicate-key",
+				Message: fmt.Sprintf("%s is already defined on line %d", st.key, first)})
+		} else {
+			defined[st.key] = st.line
+		}
+		if st.quote == 0 && cutByComment(doc.lines[st.line-1][st.endCol:]) {
+			issues = append(issues, Issue{Line: st.line, Rule: "unquoted-hash",
+				Message: fmt.Sprintf("unquoted value of %s is cut at ' #', quote it if the rest of the line belongs to it", st.key)})
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return a.Line - b.Line
+	})
+	return issues, nil
+}
+
+// cutByComment reports whether rest, the end of a line following an unquoted
+// value, is a comment that looks like the continuation of the value, such as
+// "#def" rather than "# note".
+func cutByComment(rest string) bool {
+	trimmed := strings.TrimLeft(rest, " \t")
+	comment, ok := strings.CutPrefix(trimmed, "#")
+	if !ok || trimmed == rest {
+		return false
+	}
+	return comment != "" && comment[0] != ' ' && comment[0] != '\t'
+}
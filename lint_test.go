@@ -0,0 +1,49 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("\xEF\xBB\xBFAPP_NAME=api\r\n"+
+		"# comment \n"+
+		"APP_COLOR=#fff\n"+
+		"APP_PORT=8080 # http\n"+
+		"app.mode=dev\n"+
+		"APP-URL=x\n"+
+		"APP_NAME=\"web\"\t\n"+
+		"APP_HASH=\"a#b\"\n"+
+		"APP_PASS=abc #def\n"+
+		"APP_TAG=x#y\n"), 0o644)
+
+	issues, err := Lint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Issue{
+		{Line: 1, Rule: "bom", Message: "file starts with a UTF-8 byte order mark"},
+		{Line: 1, Rule: "crlf", Message: "line ends with CRLF"},
+		{Line: 2, Rule: "trailing-whitespace", Message: "line ends with whitespace"},
+		{Line: 5, Rule: "invalid-key", Message: "app.mode is not a portable variable name"},
+		{Line: 6, Rule: "syntax", Message: `unexpected character '-' in variable name "APP-URL"`},
+		{Line: 7, Rule: "trailing-whitespace", Message: "line ends with whitespace"},
+		{Line: 7, Rule: "duplicate-key", Message: "APP_NAME is already defined on line 1"},
+		{Line: 9, Rule: "unquoted-hash", Message: "unquoted value of APP_PASS is cut at ' #', quote it if the rest of the line belongs to it"},
+	}, issues)
+	assert.Equal(t, "line 7: APP_NAME is already defined on line 1 (duplicate-key)", issues[6].String())
+}
+
+func TestLint_Clean(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("# app\nAPP_NAME=api\nAPP_CERT=\"multi\nline\"\n"), 0o644)
+
+	issues, err := Lint(path)
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+
+	_, err = Lint(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package udotenv
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LoadedKey describes a variable set in the environment by a load.
+type LoadedKey struct {
+	Key string
+	// Source is the path of the file the value comes from, "source N" for
+	// the Nth of the `Sources` of the config, or empty for the content loaded
+	// with LoadReader or LoadString.
+	Source string
+	// Overridden reports whether the variable was already in the environment
+	// and overwritten by the load. Variables set by a previous load are not
+	// overridden.
+	Overridden bool
+}
+
+// LoadedKeys returns the variables set in the environment by the last call to
+// Load, Reload, LoadReader, LoadString or LoadFS, sorted by key. Variables of
+// the files that were skipped, because they were already in the environment
+// and not overloaded, are left out.
+func (ue *UdotEnv) LoadedKeys() []LoadedKey {
+	ue.mu.RLock()
+	defer ue.mu.RUnlock()
+	return slices.Clone(ue.loaded)
+}
+
+// String formats k as "KEY from source", followed by "(overrides the
+// environment)" if it is overridden.
+func (k LoadedKey) String() string {
+	s := k.Key
+	if k.Source != "" {
+		s = fmt.Sprintf("%s from %s", s, k.Source)
+	}
+	if k.Overridden {
+		s += " (overrides the environment)"
+	}
+	return s
+}
+
+// Plan reads and checks the env files like Load, and returns the variables
+// that Load would set, sorted by key, without touching the environment.
+// Secrets and file references are resolved, so Plan fails whenever Load
+// would. The `OnBeforeSet` hook of the config, which may have side effects,
+// is not called: the values are the ones it would be passed, and the
+// variables it would veto are listed and checked.
+func (ue *UdotEnv) Plan() ([]LoadedKey, error) {
+	ue.mu.Lock()
+	defer ue.mu.Unlock()
+	return ue.plan()
+}
+
+// plan implements Plan; the caller holds the lock.
+func (ue *UdotEnv) plan() ([]LoadedKey, error) {
+	layers, readErr := ue.fileLayers()
+	if readErr != nil && !ue.continueOnError() {
+		return nil, readErr
+	}
+
+	merged, err := ue.resolve(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := ue.prepare(merged, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return ue.planned(merged, pending), readErr
+}
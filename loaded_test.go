@@ -0,0 +1,94 @@
+package udotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadedKeys(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.env")
+	second := filepath.Join(dir, "second.env")
+	_ = os.WriteFile(first, []byte("LOADED_A=1\nLOADED_B=1\nLOADED_C=1\nLOADED_REF_FILE="+second+"\n"), 0o644)
+	_ = os.WriteFile(second, []byte("LOADED_B=2\n"), 0o644)
+	for _, k := range []string{"LOADED_A", "LOADED_B", "LOADED_C", "LOADED_REF", "LOADED_REF_FILE", "LOADED_SRC"} {
+		defer os.Unsetenv(k)
+	}
+	os.Setenv("LOADED_C", "env")
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			Precedence:    LastWins,
+			FileRefSuffix: "_FILE",
+			KeyPattern:    regexp.MustCompile("^LOADED_"),
+			Sources: []Source{SourceFunc(func(context.Context) (map[string]string, error) {
+				return map[string]string{"LOADED_SRC": "1"}, nil
+			})},
+		},
+		EnvParam: stringSlice{first, second},
+	}
+	assert.Empty(t, udotEnv.LoadedKeys())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, []LoadedKey{
+		{Key: "LOADED_A", Source: first},
+		{Key: "LOADED_B", Source: second},
+		{Key: "LOADED_REF", Source: first},
+		{Key: "LOADED_REF_FILE", Source: first},
+		{Key: "LOADED_SRC", Source: "source 0"},
+	}, udotEnv.LoadedKeys())
+
+	udotEnv.OverloadParam = true
+	assert.NoError(t, udotEnv.Load())
+	assert.Contains(t, udotEnv.LoadedKeys(), LoadedKey{Key: "LOADED_A", Source: first})
+	assert.Contains(t, udotEnv.LoadedKeys(), LoadedKey{Key: "LOADED_C", Source: first, Overridden: true})
+}
+
+func TestPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("PLAN_NEW=1\nPLAN_KEPT=1\nPLAN_OVER=1\n"), 0o644)
+	t.Setenv("PLAN_KEPT", "env")
+	t.Setenv("PLAN_OVER", "env")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	plan, err := udotEnv.Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []LoadedKey{{Key: "PLAN_NEW", Source: path}}, plan)
+	_, ok := os.LookupEnv("PLAN_NEW")
+	assert.False(t, ok)
+
+	udotEnv.OverloadParam = true
+	plan, err = udotEnv.Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, "PLAN_KEPT from "+path+" (overrides the environment)", plan[0].String())
+	assert.Len(t, plan, 3)
+
+	// the hook is only called when setting the variables
+	udotEnv.Config.OnBeforeSet = func(k, v string) (string, bool) {
+		t.Errorf("OnBeforeSet called for %s", k)
+		return v, true
+	}
+	plan, err = udotEnv.Plan()
+	assert.NoError(t, err)
+	assert.Len(t, plan, 3)
+
+	udotEnv.Config.RequiredKeys = []string{"PLAN_MISSING"}
+	_, err = udotEnv.Plan()
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "env", os.Getenv("PLAN_OVER"))
+}
+
+func TestLoad_DryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("DRYRUN_A=1\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}, DryRunParam: true}
+	assert.NoError(t, udotEnv.Load())
+	_, ok := os.LookupEnv("DRYRUN_A")
+	assert.False(t, ok)
+	assert.Empty(t, udotEnv.LoadedKeys())
+}
@@ -0,0 +1,73 @@
+package udotenv
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultMaskedKeys are the `MaskedKeys` of the default configuration.
+var defaultMaskedKeys = []string{"*TOKEN*", "*SECRET*", "*PASSWORD*", "*KEY*"}
+
+// Mask returns a placeholder for the secret value, giving only its length.
+func Mask(value string) string {
+	return fmt.Sprintf("[masked, %d bytes]", len(value))
+}
+
+// MaskValue returns value, or the placeholder of Mask if key matches the
+// `MaskedKeys` of the config. The keys are matched regardless of case. It
+// is meant for the diagnostics of the application, in the same way udotenv
+// masks the values in its own errors.
+func (ue *UdotEnv) MaskValue(key, value string) string {
+	if ue.masked(key) {
+		return Mask(value)
+	}
+	return value
+}
+
+// masked reports whether the values of key are masked in the diagnostics.
+func (ue *UdotEnv) masked(key string) bool {
+	if ue.Config == nil {
+		return false
+	}
+	return slices.ContainsFunc(ue.Config.MaskedKeys, func(pattern string) bool {
+		ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(key))
+		return ok
+	})
+}
+
+// quote returns value quoted for an error message, or masked if key matches
+// the `MaskedKeys` of the config.
+func (ue *UdotEnv) quote(key, value string) string {
+	if ue.masked(key) {
+		return Mask(value)
+	}
+	return strconv.Quote(value)
+}
+
+// maskError returns err, an error about the value of key, with value masked
+// in its message if key matches the `MaskedKeys` of the config. The returned
+// error still wraps err.
+func (ue *UdotEnv) maskError(key, value string, err error) error {
+	if err == nil || value == "" || !ue.masked(key) {
+		return err
+	}
+	msg := strings.ReplaceAll(err.Error(), strconv.Quote(value), Mask(value))
+	return &maskedError{err: err, msg: strings.ReplaceAll(msg, value, Mask(value))}
+}
+
+// maskedError is an error whose message hides a secret value.
+type maskedError struct {
+	err error
+	msg string
+}
+
+func (e *maskedError) Error() string {
+	return e.msg
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
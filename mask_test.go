@@ -0,0 +1,53 @@
+package udotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskValue(t *testing.T) {
+	udotEnv := &UdotEnv{Config: GetDefaultConfig()}
+
+	assert.Equal(t, "[masked, 6 bytes]", udotEnv.MaskValue("API_TOKEN", "abc123"))
+	assert.Equal(t, "[masked, 2 bytes]", udotEnv.MaskValue("db_password", "pw"))
+	assert.Equal(t, "[masked, 0 bytes]", udotEnv.MaskValue("AWS_SECRET_ACCESS_KEY", ""))
+	assert.Equal(t, "api", udotEnv.MaskValue("APP_NAME", "api"))
+	assert.Equal(t, "abc123", (&UdotEnv{Config: &Config{}}).MaskValue("API_TOKEN", "abc123"))
+	assert.Equal(t, "abc123", (&UdotEnv{}).MaskValue("API_TOKEN", "abc123"))
+}
+
+func TestMask_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("SIGNING_KEY=hunter2\nFEATURE=maybe\nPORT=http\n"), 0o644)
+	defer os.Unsetenv("SIGNING_KEY")
+	defer os.Unsetenv("FEATURE")
+	defer os.Unsetenv("PORT")
+
+	config := GetDefaultConfig()
+	config.NormalizeBools = []string{"SIGNING_KEY"}
+	err := (&UdotEnv{Config: config, EnvParam: stringSlice{path}}).Load()
+	assert.EqualError(t, err, "invalid boolean value [masked, 7 bytes] for SIGNING_KEY")
+
+	config = GetDefaultConfig()
+	config.Schema = Schema{
+		"SIGNING_KEY": {Pattern: regexp.MustCompile(`^[0-9a-f]+$`)},
+		"FEATURE":     {Type: KindBool},
+	}
+	err = (&UdotEnv{Config: config, EnvParam: stringSlice{path}}).Load()
+	assert.EqualError(t, err, `validation failed: FEATURE: "maybe" is not a valid bool; SIGNING_KEY: [masked, 7 bytes] does not match ^[0-9a-f]+$`)
+
+	config = GetDefaultConfig()
+	config.MaskedKeys = append(config.MaskedKeys, "PORT")
+	udotEnv := &UdotEnv{Config: config, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	_, err = udotEnv.GetInt("PORT")
+	assert.EqualError(t, err, `PORT: strconv.Atoi: parsing [masked, 4 bytes]: invalid syntax`)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	assert.NotContains(t, err.Error(), "http")
+}
@@ -0,0 +1,42 @@
+package udotenv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseBool parses a boolean value leniently. Besides the values accepted by
+// strconv.ParseBool, it accepts "yes", "no", "y", "n", "on" and "off" in any
+// case.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// normalizeBools rewrites the values of the `NormalizeBools` keys of the
+// config to "true" or "false".
+func (ue *UdotEnv) normalizeBools(vars map[string]string) error {
+	if ue.Config == nil {
+		return nil
+	}
+
+	for _, k := range ue.Config.NormalizeBools {
+		v, ok := vars[k]
+		if !ok {
+			continue
+		}
+
+		b, err := parseBool(v)
+		if err != nil {
+			return withSentinel(fmt.Errorf("invalid boolean value %s for %s", ue.quote(k, v), k), ErrValidation)
+		}
+		vars[k] = strconv.FormatBool(b)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package udotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBool(t *testing.T) {
+	for _, v := range []string{"true", "True", "1", "yes", "Y", "on", "ON"} {
+		b, err := parseBool(v)
+		assert.NoError(t, err, v)
+		assert.True(t, b, v)
+	}
+
+	for _, v := range []string{"false", "FALSE", "0", "no", "n", "off"} {
+		b, err := parseBool(v)
+		assert.NoError(t, err, v)
+		assert.False(t, b, v)
+	}
+
+	_, err := parseBool("maybe")
+	assert.Error(t, err)
+}
+
+func TestLoad_NormalizeBools(t *testing.T) {
+	_ = godotenv.Write(map[string]string{
+		"NORMALIZE_ON":    "On",
+		"NORMALIZE_NO":    "no",
+		"NORMALIZE_OTHER": "yes",
+	}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("NORMALIZE_ON")
+	defer os.Unsetenv("NORMALIZE_NO")
+	defer os.Unsetenv("NORMALIZE_OTHER")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{NormalizeBools: []string{"NORMALIZE_ON", "NORMALIZE_NO", "NORMALIZE_MISSING"}},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "true", os.Getenv("NORMALIZE_ON"))
+	assert.Equal(t, "false", os.Getenv("NORMALIZE_NO"))
+	assert.Equal(t, "yes", os.Getenv("NORMALIZE_OTHER"))
+}
+
+func TestLoad_NormalizeBoolsInvalid(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"NORMALIZE_BAD": "maybe"}, ".test.env")
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{NormalizeBools: []string{"NORMALIZE_BAD"}},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.EqualError(t, udotEnv.Load(), `invalid boolean value "maybe" for NORMALIZE_BAD`)
+}
@@ -0,0 +1,125 @@
+package udotenv
+
+import (
+	"errors"
+	"flag"
+)
+
+// options collects the settings applied by the Option functions.
+type options struct {
+	config  *Config
+	flagSet *flag.FlagSet
+	args    []string
+	parse   bool
+}
+
+// Option configures the instance created by NewWithOptions.
+type Option func(*options)
+
+// setConfig applies set to the config, unless it was replaced by a nil one,
+// which NewWithOptions reports.
+func (o *options) setConfig(set func(*Config)) {
+	if o.config != nil {
+		set(o.config)
+	}
+}
+
+// WithConfig uses config as the base configuration. The options that follow
+// it modify config, which must not be nil.
+func WithConfig(config *Config) Option {
+	return func(o *options) {
+		o.config = config
+	}
+}
+
+// WithFlags sets the names of the env flags.
+func WithFlags(names ...string) Option {
+	return func(o *options) {
+		o.setConfig(func(c *Config) { c.EnvFlags = names })
+	}
+}
+
+// WithOverloadFlags sets the names of the overload flags.
+func WithOverloadFlags(names ...string) Option {
+	return func(o *options) {
+		o.setConfig(func(c *Config) { c.OverloadFlags = names })
+	}
+}
+
+// WithDefaultPath sets the path loaded when an env flag is passed without a
+// value.
+func WithDefaultPath(path string) Option {
+	return func(o *options) {
+		o.setConfig(func(c *Config) { c.DefaultEnvPath = path })
+	}
+}
+
+// WithOverloadByDefault makes existing environment variables overloaded
+// unless the overload flag is set to false.
+func WithOverloadByDefault() Option {
+	return func(o *options) {
+		o.setConfig(func(c *Config) { c.OverloadByDefault = true })
+	}
+}
+
+// WithFlagSet registers the flags on fs instead of the global flag set.
+func WithFlagSet(fs *flag.FlagSet) Option {
+	return func(o *options) {
+		o.flagSet = fs
+	}
+}
+
+// WithArgs parses args, the command-line arguments without the program name,
+// right after the flags are registered.
+func WithArgs(args []string) Option {
+	return func(o *options) {
+		o.args = args
+		o.parse = true
+	}
+}
+
+// NewWithOptions creates a new instance of UdotEnv configured by opts. It
+// starts from the default configuration and registers the flags on the
+// global flag set unless WithFlagSet is passed. Flags are only parsed when
+// WithArgs is passed.
+//
+// It is the option-based form of New, which keeps its signature for
+// compatibility. Unlike New, it reports problems as errors instead of
+// panicking, an invalid configuration as a *ConfigError (see
+// Config.Validate):
+//
+//	udotEnv, err := udotenv.NewWithOptions(
+//	    udotenv.WithFlags("env", "e"),
+//	    udotenv.WithDefaultPath(".env.local"),
+//	    udotenv.WithArgs(os.Args[1:]),
+//	)
+func NewWithOptions(opts ...Option) (*UdotEnv, error) {
+	o := &options{config: GetDefaultConfig(), flagSet: flag.CommandLine}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		return nil, &ConfigError{Msg: "config must not be nil"}
+	}
+	if o.flagSet == nil {
+		return nil, errors.New("flag set must not be nil")
+	}
+
+	udotEnv, err := newWithFlagSet(o.flagSet, o.config)
+	if err != nil {
+		return nil, err
+	}
+	if !o.parse {
+		return udotEnv, nil
+	}
+
+	args, err := udotEnv.prepareArgs(o.args)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	return udotEnv, nil
+}
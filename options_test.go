@@ -0,0 +1,46 @@
+package udotenv
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	udotEnv, err := NewWithOptions(
+		WithFlagSet(fs),
+		WithFlags("env"),
+		WithOverloadFlags("over"),
+		WithDefaultPath(".custom.env"),
+		WithOverloadByDefault(),
+		WithArgs([]string{"-env", "--", "rest"}),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"env"}, udotEnv.Config.EnvFlags)
+	assert.Equal(t, stringSlice{".custom.env"}, udotEnv.EnvParam)
+	assert.True(t, udotEnv.OverloadParam)
+	assert.Equal(t, []string{"rest"}, fs.Args())
+}
+
+func TestNewWithOptions_Errors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("e", "", "")
+
+	_, err := NewWithOptions(WithFlagSet(fs))
+	assert.EqualError(t, err, `invalid config: EnvFlags: flag "e" is already defined on the flag set`)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = NewWithOptions(WithConfig(nil))
+	assert.EqualError(t, err, "invalid config: config must not be nil")
+	_, err = NewWithOptions(WithConfig(nil), WithFlags("e"), WithOverloadByDefault())
+	assert.EqualError(t, err, "invalid config: config must not be nil")
+
+	_, err = NewWithOptions(
+		WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		WithArgs([]string{"-o", "-eo"}),
+	)
+	assert.EqualError(t, err, "only one flag per param must be passed")
+}
@@ -0,0 +1,337 @@
+package udotenv
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	charComment      = '#'
+	singleQuote      = '\''
+	doubleQuote      = '"'
+	exportPrefix     = "export"
+	includeDirective = "#include"
+	includeKey       = "dotenv_include"
+)
+
+// statement is a single `KEY=value` assignment of an env file.
+type statement struct {
+	key    string
+	value  string // value with quotes removed and escapes resolved, not expanded
+	raw    string // value as written in the file, quotes included
+	quote  byte   // quote character of the value, 0 if unquoted
+	export bool
+	padded bool // unquoted value surrounded by whitespace
+
+	// The value spans from column col of line to column endCol of endLine,
+	// where lines are 1-based and columns are 0-based byte offsets.
+	line, col       int
+	endLine, endCol int
+}
+
+// document is a parsed env file that keeps the original lines, so it can be
+// written back with comments, blank lines and ordering intact.
+type document struct {
+	lines      []string
+	statements []statement
+	includes   []include
+}
+
+// include is an include directive of an env file, either a `#include path`
+// line or a `dotenv_include=path` statement.
+type include struct {
+	path string
+	line int
+}
+
+// ParseError is a syntax error in env content.
+type ParseError struct {
+	File   string // path of the file, empty for content not read from a file
+	Line   int    // 1-based line of the error
+	Column int    // 1-based byte column of the error
+	Text   string // offending text
+	Msg    string
+}
+
+// Is makes a *ParseError match ErrParse.
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// Error returns the position and the description of the error. The file is
+// left out, since the errors about a file already name it.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// parseDocument parses src in the dotenv syntax understood by godotenv:
+// optional `export` prefixes, `=` or `:` separators, single- and
+// double-quoted values that may span several lines, and `#` comments.
+func parseDocument(src []byte) (*document, error) {
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	doc := &document{lines: strings.Split(string(src), "\n")}
+
+	for i := 0; i < len(doc.lines); i++ {
+		line := doc.lines[i]
+		start := indexNonSpace(line, 0)
+		if start == len(line) {
+			continue
+		}
+		if line[start] == charComment {
+			if path, ok := includePath(line[start:]); ok {
+				doc.includes = append(doc.includes, include{path: path, line: i + 1})
+			}
+			continue
+		}
+
+		st, err := doc.parseStatement(i, start)
+		if err != nil {
+			return nil, err
+		}
+		if st.key == includeKey {
+			doc.includes = append(doc.includes, include{path: st.value, line: st.line})
+		} else {
+			doc.statements = append(doc.statements, st)
+		}
+		i = st.endLine - 1
+	}
+	return doc, nil
+}
+
+// includePath returns the path of a `#include path` comment line.
+func includePath(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, includeDirective)
+	if !ok || rest == "" || !isSpace(rune(rest[0])) {
+		return "", false
+	}
+
+	path := strings.TrimSpace(rest)
+	if len(path) >= 2 && (path[0] == doubleQuote || path[0] == singleQuote) && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	}
+	return path, path != ""
+}
+
+// parseStatement parses the statement starting at column start of the line
+// with index i.
+func (doc *document) parseStatement(i, start int) (statement, error) {
+	line := doc.lines[i]
+	st := statement{line: i + 1}
+
+	rest := line[start:]
+	if strings.HasPrefix(rest, exportPrefix) {
+		trimmed := rest[len(exportPrefix):]
+		if trimmed != "" && isSpace(rune(trimmed[0])) {
+			st.export = true
+			start = indexNonSpace(line, start+len(exportPrefix))
+		}
+	}
+
+	sep := strings.IndexAny(line[start:], "=:")
+	if sep == -1 {
+		text := strings.TrimSpace(line[start:])
+		return st, &ParseError{Line: st.line, Column: start + 1, Text: text, Msg: fmt.Sprintf("missing '=' after %q", text)}
+	}
+	st.key = strings.TrimRightFunc(line[start:start+sep], isSpace)
+	if st.key == "" {
+		return st, &ParseError{Line: st.line, Column: start + 1, Text: line[start:], Msg: "empty variable name"}
+	}
+	for j, r := range st.key {
+		if !isKeyChar(r) {
+			return st, &ParseError{Line: st.line, Column: start + j + 1, Text: st.key,
+				Msg: fmt.Sprintf("unexpected character %q in variable name %q", r, st.key)}
+		}
+	}
+
+	st.col = indexNonSpace(line, start+sep+1)
+	leading := st.col > start+sep+1
+	if st.col < len(line) && (line[st.col] == singleQuote || line[st.col] == doubleQuote) {
+		return st, doc.parseQuoted(&st)
+	}
+
+	st.endLine = st.line
+	st.endCol = len(line)
+	value := line[st.col:]
+	// like godotenv, the last "#" preceded by a space starts a comment
+	pos := max(strings.LastIndex(value, " #"), strings.LastIndex(value, "\t#"))
+	if pos != -1 {
+		value = value[:pos]
+	}
+	trimmed := strings.TrimRightFunc(value, isSpace)
+	// whitespace aligning a trailing comment is not padding
+	st.padded = trimmed != "" && (leading || (pos == -1 && trimmed != value))
+	value = trimmed
+	st.endCol = st.col + len(value)
+	st.raw = value
+	st.value = value
+	return st, nil
+}
+
+// parseQuoted parses the quoted value of st, which may continue on the
+// following lines.
+func (doc *document) parseQuoted(st *statement) error {
+	st.quote = doc.lines[st.line-1][st.col]
+
+	var value strings.Builder
+	var raw strings.Builder
+	raw.WriteByte(st.quote)
+
+	i, j := st.line-1, st.col+1
+	for {
+		line := doc.lines[i]
+		for ; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case c == st.quote:
+				raw.WriteByte(c)
+				st.raw = raw.String()
+				st.value = value.String()
+				st.endLine, st.endCol = i+1, j+1
+				return doc.checkTrailing(st)
+			case c == '\\' && j+1 < len(line) && st.quote == doubleQuote:
+				raw.WriteString(line[j : j+2])
+				value.WriteString(unescape(line[j+1]))
+				j++
+			case c == '\\' && j+1 < len(line) && line[j+1] == singleQuote && st.quote == singleQuote:
+				raw.WriteString(line[j : j+2])
+				value.WriteString(line[j : j+2])
+				j++
+			default:
+				raw.WriteByte(c)
+				value.WriteByte(c)
+			}
+		}
+
+		if i++; i == len(doc.lines) {
+			return &ParseError{Line: st.line, Column: st.col + 1, Text: doc.lines[st.line-1][st.col:],
+				Msg: fmt.Sprintf("unterminated quoted value for %s", st.key)}
+		}
+		raw.WriteByte('\n')
+		value.WriteByte('\n')
+		j = 0
+	}
+}
+
+// checkTrailing verifies that only a comment follows the quoted value of st.
+func (doc *document) checkTrailing(st *statement) error {
+	line := doc.lines[st.endLine-1]
+	col := indexNonSpace(line, st.endCol)
+	if rest := line[col:]; rest != "" && rest[0] != charComment {
+		return &ParseError{Line: st.endLine, Column: col + 1, Text: rest,
+			Msg: fmt.Sprintf("unexpected %q after quoted value of %s", rest, st.key)}
+	}
+	return nil
+}
+
+// unescape resolves the escape sequence of c inside a double-quoted value.
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case '$':
+		// kept escaped so that expansion leaves it alone
+		return `\$`
+	}
+	return string(c)
+}
+
+var expandVarRegex = regexp.MustCompile(`(\\)?(\$)(\()?\{?([A-Z0-9_]+)?\}?`)
+
+// expandVariables replaces `$KEY` and `${KEY}` references in v with the values
+// from vars, the same way godotenv does. Escaped references (`\$KEY`) are
+// kept literally.
+func expandVariables(v string, vars map[string]string) string {
+	return expandVarRegex.ReplaceAllStringFunc(v, func(s string) string {
+		submatch := expandVarRegex.FindStringSubmatch(s)
+		if submatch[1] == "\\" || submatch[3] == "(" {
+			return submatch[0][1:]
+		} else if submatch[4] != "" {
+			return vars[submatch[4]]
+		}
+		return s
+	})
+}
+
+// vars returns the variables defined by doc. Later definitions of a key win,
+// and references to previously defined keys are expanded in unquoted and
+// double-quoted values.
+func (doc *document) vars() map[string]string {
+	vars := make(map[string]string, len(doc.statements))
+	for _, st := range doc.statements {
+		assign(vars, st, false)
+	}
+	return vars
+}
+
+// assign sets the variable defined by st in vars. Unless raw is set, the
+// references to the variables of vars are expanded in unquoted and
+// double-quoted values. With raw, for `Config.Expand`, the value is kept
+// unexpanded, and the `$` of a single-quoted value are escaped so that they
+// stay literal.
+func assign(vars map[string]string, st statement, raw bool) {
+	switch {
+	case st.quote == singleQuote && raw:
+		vars[st.key] = strings.ReplaceAll(st.value, "$", `\$`)
+	case st.quote == singleQuote || raw:
+		vars[st.key] = st.value
+	default:
+		vars[st.key] = expandVariables(st.value, vars)
+	}
+}
+
+// setValue replaces the value of st with value in the lines of doc. The
+// value is quoted as needed; the text around it is kept as is.
+func (doc *document) setValue(st statement, value string) {
+	first := doc.lines[st.line-1][:st.col]
+	last := doc.lines[st.endLine-1][st.endCol:]
+
+	lines := append([]string{}, doc.lines[:st.line-1]...)
+	lines = append(lines, first+quoteValue(value)+last)
+	doc.lines = append(lines, doc.lines[st.endLine:]...)
+}
+
+// bytes returns the content of doc.
+func (doc *document) bytes() []byte {
+	return []byte(strings.Join(doc.lines, "\n"))
+}
+
+var bareValueRegex = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+// quoteValue formats value for an env file. Values made of safe characters
+// are written bare, others are double-quoted with escapes.
+func quoteValue(value string) string {
+	if bareValueRegex.MatchString(value) {
+		return value
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+	return `"` + r.Replace(value) + `"`
+}
+
+func indexNonSpace(s string, from int) int {
+	for i := from; i < len(s); i++ {
+		if !isSpace(rune(s[i])) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// isSpace reports whether r is a space character other than a line break.
+func isSpace(r rune) bool {
+	switch r {
+	case '\t', '\v', '\f', '\r', ' ', 0x85, 0xA0:
+		return true
+	}
+	return false
+}
+
+// isKeyChar reports whether r may appear in a variable name.
+func isKeyChar(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsNumber(r)
+}
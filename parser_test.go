@@ -0,0 +1,68 @@
+package udotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDocument_GodotenvCompatible(t *testing.T) {
+	src := "# comment\n" +
+		"PLAIN=value\n" +
+		"export EXPORTED=yes\n" +
+		"SPACED = padded value   \n" +
+		"COMMENTED=value # comment\n" +
+		"HASH=value#nocomment\n" +
+		"YAML: style\n" +
+		"SINGLE='single $PLAIN \\n'\n" +
+		"DOUBLE=\"double $PLAIN \\n \\\"quoted\\\" end\"\n" +
+		"BRACED=${PLAIN}-x\n" +
+		"ESCAPED=\\$PLAIN\n" +
+		"MULTI=\"first\nsecond\"\n" +
+		"EMPTY=\n" +
+		"QUOTED_COMMENT='a # b' # comment\n"
+
+	doc, err := parseDocument([]byte(src))
+	assert.NoError(t, err)
+
+	expected, err := godotenv.Unmarshal(src)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, doc.vars())
+}
+
+func TestParseDocument_Errors(t *testing.T) {
+	_, err := parseDocument([]byte("OK=1\nBAD KEY=1\n"))
+	assert.EqualError(t, err, `line 2, column 4: unexpected character ' ' in variable name "BAD KEY"`)
+
+	_, err = parseDocument([]byte("OK=1\nNOVALUE\n"))
+	assert.EqualError(t, err, `line 2, column 1: missing '=' after "NOVALUE"`)
+
+	_, err = parseDocument([]byte("OK=\"1\n"))
+	assert.EqualError(t, err, "line 1, column 4: unterminated quoted value for OK")
+}
+
+func TestLoad_ParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("OK=1\nQUOTED=\"value\" trailing\n"), 0o644)
+
+	udotEnv := &UdotEnv{EnvParam: stringSlice{path}}
+	err := udotEnv.Load()
+	assert.EqualError(t, err, "error loading file '"+path+"': line 2, column 16: unexpected \"trailing\" after quoted value of QUOTED")
+
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, &ParseError{File: path, Line: 2, Column: 16, Text: "trailing",
+		Msg: `unexpected "trailing" after quoted value of QUOTED`}, pe)
+}
+
+func TestQuoteValue(t *testing.T) {
+	for _, v := range []string{"", "plain", "with space", "$HOME", "a\nb", `back\slash`, `"quoted"`, "it's"} {
+		doc, err := parseDocument([]byte("KEY=" + quoteValue(v)))
+		assert.NoError(t, err, v)
+		assert.Equal(t, v, doc.vars()["KEY"], v)
+	}
+}
@@ -0,0 +1,307 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PathMode defines what the relative `DefaultEnvPath` is relative to.
+type PathMode int
+
+const (
+	// RelativeToWorkingDir resolves the path from the working directory.
+	RelativeToWorkingDir PathMode = iota
+	// RelativeToExecutable resolves the path from the directory of the
+	// executable, as given by os.Executable, so that a deployed binary finds
+	// its .env wherever it is started from.
+	RelativeToExecutable
+	// RelativeToCaller resolves the path from the directory of the source
+	// file that created the UdotEnv instance, which also works with
+	// `go run`, whose executables live in a temporary directory.
+	RelativeToCaller
+)
+
+// defaultPath returns the `DefaultEnvPath` of the config resolved according
+// to its `DefaultEnvPathMode`. Paths starting with `~` or `$` are left to
+// expandPath.
+func (ue *UdotEnv) defaultPath() string {
+	path := ue.Config.DefaultEnvPath
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "~") || strings.HasPrefix(path, "$") {
+		return path
+	}
+
+	switch ue.Config.DefaultEnvPathMode {
+	case RelativeToExecutable:
+		if exe, err := os.Executable(); err == nil {
+			if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+				exe = resolved
+			}
+			return filepath.Join(filepath.Dir(exe), path)
+		}
+	case RelativeToCaller:
+		if dir, ok := callerDir(); ok {
+			return filepath.Join(dir, path)
+		}
+	}
+	return path
+}
+
+// callerDir returns the directory of the source file of the first caller
+// outside of this package, its tests excepted.
+func callerDir() (string, bool) {
+	_, self, _, ok := runtime.Caller(0)
+	if !ok {
+		return "", false
+	}
+	pkgDir := filepath.Dir(self)
+
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if frame.File != "" && (filepath.Dir(frame.File) != pkgDir || strings.HasSuffix(frame.File, "_test.go")) {
+			return filepath.Dir(frame.File), true
+		}
+		if !more {
+			return "", false
+		}
+	}
+}
+
+// envFile is an env file passed through the env flags.
+type envFile struct {
+	param string // path as passed
+	path  string // path to read
+	glob  bool   // path is a pattern matching no file
+}
+
+// envFiles resolves the paths of the files passed through the env flags. A
+// leading `~` and the environment variables of the paths are expanded (see
+// expandPath), glob patterns are replaced by the files they match (see
+// globFiles) and, with `SearchParents` set in the config, a relative path
+// missing from the working directory is looked up in its parents (see
+// findInParents). Directories are replaced by their `*.env` files (see
+// dirFiles). When no file is passed and `UseXDG` is set in the config,
+// the file is looked up in the XDG locations instead (see xdgFile).
+func (ue *UdotEnv) envFiles() []envFile {
+	params := ue.envParams()
+	if len(params) == 0 && ue.Config != nil && ue.Config.UseXDG {
+		if f, ok := ue.xdgFile(); ok {
+			return []envFile{f}
+		}
+		return nil
+	}
+
+	files := make([]envFile, 0, len(params))
+	for _, param := range params {
+		if isRemote(param) {
+			files = append(files, envFile{param: param, path: param})
+			continue
+		}
+
+		path := expandPath(param)
+		if isGlob(path) {
+			files = append(files, globFiles(param, path)...)
+			continue
+		}
+		if ue.Config != nil && ue.Config.SearchParents {
+			path = findInParents(path)
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			files = append(files, dirFiles(param, path)...)
+			continue
+		}
+		files = append(files, envFile{param: param, path: path})
+	}
+	return files
+}
+
+// envParams returns the values of the env flags, split on the
+// `PathSeparators` of the config (see splitPaths). Without env flags, the
+// files are taken from the `PathEnv` variable of the config.
+func (ue *UdotEnv) envParams() []string {
+	if ue.Config == nil {
+		return ue.EnvParam
+	}
+	if len(ue.EnvParam) == 0 && ue.Config.PathEnv != "" {
+		separators := ue.Config.PathSeparators
+		if separators == "" {
+			separators = ","
+		}
+		return splitPaths(os.Getenv(ue.Config.PathEnv), separators)
+	}
+	if ue.Config.PathSeparators == "" {
+		return ue.EnvParam
+	}
+	var params []string
+	for _, param := range ue.EnvParam {
+		params = append(params, splitPaths(param, ue.Config.PathSeparators)...)
+	}
+	return params
+}
+
+// splitPaths splits value on any of the characters of separators, skipping
+// empty paths. A `:` that starts the `//` of a URL or the port of its host
+// does not split.
+func splitPaths(value, separators string) []string {
+	var paths []string
+	start := 0
+	for i, r := range value {
+		if !strings.ContainsRune(separators, r) || r == ':' && urlColon(value[start:i], value[i+1:]) {
+			continue
+		}
+		if i > start {
+			paths = append(paths, value[start:i])
+		}
+		start = i + 1
+	}
+	if start < len(value) {
+		paths = append(paths, value[start:])
+	}
+	return paths
+}
+
+// urlColon reports whether the `:` between before and after belongs to a
+// URL, either as the end of its scheme or as the start of a port.
+func urlColon(before, after string) bool {
+	if strings.HasPrefix(after, "//") {
+		return true
+	}
+	return strings.Contains(before, "://") && after != "" && after[0] >= '0' && after[0] <= '9'
+}
+
+// dirFiles returns the `*.env` files of the directory dir, in lexical order,
+// like the drop-in fragments of a conf.d directory. An empty directory yields
+// no file.
+func dirFiles(param, dir string) []envFile {
+	entries, _ := os.ReadDir(dir)
+	var files []envFile
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".env" {
+			files = append(files, envFile{param: param, path: filepath.Join(dir, entry.Name())})
+		}
+	}
+	return files
+}
+
+// isGlob reports whether path contains glob metacharacters.
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// globFiles returns the files matching pattern, in lexical order. A pattern
+// matching nothing yields a single envFile flagged as glob, which fails to
+// load unless missing files are ignored.
+func globFiles(param, pattern string) []envFile {
+	matches, _ := filepath.Glob(pattern)
+	if len(matches) == 0 {
+		return []envFile{{param: param, path: pattern, glob: true}}
+	}
+
+	files := make([]envFile, 0, len(matches))
+	for _, path := range matches {
+		files = append(files, envFile{param: param, path: path})
+	}
+	return files
+}
+
+// expandPath expands a leading `~` of path to the home directory of the user,
+// and the `$VAR` and `${VAR}` references to the environment. An unset
+// XDG_CONFIG_HOME stands for its default (see xdgConfigHome).
+func expandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+	if !strings.Contains(path, "$") {
+		return path
+	}
+
+	return os.Expand(path, func(key string) string {
+		if v, ok := os.LookupEnv(key); ok {
+			return v
+		}
+		if key == "XDG_CONFIG_HOME" {
+			dir, _ := xdgConfigHome()
+			return dir
+		}
+		return ""
+	})
+}
+
+// xdgConfigHome returns XDG_CONFIG_HOME, or its default `~/.config` if it is
+// unset or not absolute, as the XDG Base Directory specification says. Unlike
+// os.UserConfigDir, it follows the specification on every system, macOS
+// included.
+func xdgConfigHome() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// xdgFile returns the first existing file among the `DefaultEnvPath` of the
+// config, `$XDG_CONFIG_HOME/<app>/env` and `/etc/<app>/env`, where app is
+// the `AppName` of the config or the name of the executable.
+func (ue *UdotEnv) xdgFile() (envFile, bool) {
+	app := ue.Config.AppName
+	if app == "" {
+		app = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+
+	candidates := []string{defaultEnvPath}
+	if ue.Config.DefaultEnvPath != "" {
+		candidates[0] = expandPath(ue.defaultPath())
+	}
+	if dir, err := xdgConfigHome(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, app, "env"))
+	}
+	candidates = append(candidates, filepath.Join("/etc", app, "env"))
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return envFile{param: path, path: path}, true
+		}
+	}
+	return envFile{}, false
+}
+
+// findInParents returns the relative path rel as found from the nearest of
+// the working directory and its parents, stopping at the root of the git
+// repository, i.e. the first directory containing a `.git` entry, or at the
+// root of the filesystem. Absolute and unresolved paths are returned as is.
+func findInParents(rel string) string {
+	if filepath.IsAbs(rel) {
+		return rel
+	}
+	if _, err := os.Stat(rel); err == nil {
+		return rel
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return rel
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return rel
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return rel
+		}
+		dir = parent
+
+		path := filepath.Join(dir, rel)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_SearchParents(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	sub := filepath.Join(repo, "services", "api")
+	_ = os.MkdirAll(sub, 0o755)
+	_ = os.Mkdir(filepath.Join(repo, ".git"), 0o755)
+	_ = os.WriteFile(filepath.Join(repo, ".env"), []byte("PARENTS_A=repo\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(root, "outside.env"), []byte("PARENTS_B=outside\n"), 0o644)
+	defer os.Unsetenv("PARENTS_A")
+	t.Chdir(sub)
+
+	udotEnv := &UdotEnv{Config: &Config{SearchParents: true}, EnvParam: stringSlice{".env"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "repo", os.Getenv("PARENTS_A"))
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	_ = os.WriteFile(".env", []byte("PARENTS_A=sub\n"), 0o644)
+	changed, err = udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	udotEnv = &UdotEnv{Config: &Config{SearchParents: true}, EnvParam: stringSlice{"outside.env"}}
+	assert.ErrorIs(t, udotEnv.Load(), ErrFileNotFound)
+}
+
+func TestFindInParents(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	_ = os.MkdirAll(sub, 0o755)
+	_ = os.WriteFile(filepath.Join(root, "a", "app.env"), nil, 0o644)
+	t.Chdir(sub)
+
+	assert.Equal(t, filepath.Join(root, "a", "app.env"), findInParents("app.env"))
+	assert.Equal(t, "missing.env", findInParents("missing.env"))
+	assert.Equal(t, "/abs/app.env", findInParents("/abs/app.env"))
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("EXPAND_DIR", "/srv/app")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	os.Unsetenv("XDG_CONFIG_HOME")
+
+	assert.Equal(t, home, expandPath("~"))
+	assert.Equal(t, home+"/.config/myapp/.env", expandPath("~/.config/myapp/.env"))
+	assert.Equal(t, home+"/.env", expandPath("$HOME/.env"))
+	assert.Equal(t, "/srv/app/.env", expandPath("${EXPAND_DIR}/.env"))
+	assert.Equal(t, "~user/.env", expandPath("~user/.env"))
+	assert.Equal(t, filepath.Join(home, ".config", "myapp", ".env"), filepath.Clean(expandPath("$XDG_CONFIG_HOME/myapp/.env")))
+}
+
+func TestLoad_ExpandedPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	_ = os.WriteFile(filepath.Join(home, "app.env"), []byte("EXPANDED_A=1\n"), 0o644)
+	defer os.Unsetenv("EXPANDED_A")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{"~/app.env"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "1", os.Getenv("EXPANDED_A"))
+}
+
+func TestLoad_UseXDG(t *testing.T) {
+	t.Chdir(t.TempDir())
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	_ = os.MkdirAll(filepath.Join(config, "xdgapp"), 0o755)
+	_ = os.WriteFile(filepath.Join(config, "xdgapp", "env"), []byte("XDG_A=config\n"), 0o644)
+	defer os.Unsetenv("XDG_A")
+
+	udotEnv := &UdotEnv{Config: &Config{UseXDG: true, AppName: "xdgapp"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "config", os.Getenv("XDG_A"))
+
+	_ = os.WriteFile(".env", []byte("XDG_A=local\n"), 0o644)
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "local", os.Getenv("XDG_A"))
+
+	udotEnv = &UdotEnv{Config: &Config{UseXDG: true, AppName: "xdg-missing-app"}}
+	t.Chdir(t.TempDir())
+	assert.NoError(t, udotEnv.Load())
+}
+
+func TestLoad_UseXDGDefaults(t *testing.T) {
+	t.Chdir(t.TempDir())
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	_ = os.MkdirAll(filepath.Join(home, ".config", "xdgapp"), 0o755)
+	_ = os.WriteFile(filepath.Join(home, ".config", "xdgapp", "env"), []byte("XDG_B=config\n"), 0o644)
+	defer os.Unsetenv("XDG_B")
+
+	udotEnv := &UdotEnv{Config: &Config{UseXDG: true, AppName: "xdgapp", DefaultEnvPath: "app.env"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "config", os.Getenv("XDG_B"))
+
+	_ = os.WriteFile(".env", []byte("XDG_B=dotenv\n"), 0o644)
+	_ = os.WriteFile("app.env", []byte("XDG_B=local\n"), 0o644)
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "local", os.Getenv("XDG_B"))
+}
+
+func TestPrepareArgs_DefaultEnvPathMode(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	exe, _ := os.Executable()
+	exe, _ = filepath.EvalSymlinks(exe)
+
+	for mode, expected := range map[PathMode]string{
+		RelativeToWorkingDir: "app.env",
+		RelativeToExecutable: filepath.Join(filepath.Dir(exe), "app.env"),
+		RelativeToCaller:     filepath.Join(filepath.Dir(file), "app.env"),
+	} {
+		udotEnv := &UdotEnv{Config: &Config{EnvFlags: []string{"e"}, DefaultEnvPath: "app.env", DefaultEnvPathMode: mode}}
+		assert.Equal(t, []string{"-e", expected}, udotEnv.PrepareArgs([]string{"-e"}), mode)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{EnvFlags: []string{"e"}, DefaultEnvPath: "/etc/app.env", DefaultEnvPathMode: RelativeToCaller}}
+	assert.Equal(t, []string{"-e", "/etc/app.env"}, udotEnv.PrepareArgs([]string{"-e"}))
+}
+
+func TestLoad_Glob(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.Mkdir("configs", 0o755)
+	_ = os.WriteFile("configs/b.env", []byte("GLOB_A=b\nGLOB_B=b\n"), 0o644)
+	_ = os.WriteFile("configs/a.env", []byte("GLOB_A=a\n"), 0o644)
+	_ = os.WriteFile("configs/skipped.txt", []byte("GLOB_C=c\n"), 0o644)
+	for _, k := range []string{"GLOB_A", "GLOB_B"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{Precedence: LastWins}, EnvParam: stringSlice{"configs/*.env"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "b", os.Getenv("GLOB_A"))
+	assert.Equal(t, "b", os.Getenv("GLOB_B"))
+	_, ok := os.LookupEnv("GLOB_C")
+	assert.False(t, ok)
+
+	_ = os.WriteFile("configs/c.env", []byte("GLOB_A=c\n"), 0o644)
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	udotEnv = &UdotEnv{Config: &Config{}, EnvParam: stringSlice{"missing/*.env"}}
+	err = udotEnv.Load()
+	assert.EqualError(t, err, "no file matches 'missing/*.env'")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+
+	udotEnv.Config.OptionalFiles = []string{"missing/*.env"}
+	assert.NoError(t, udotEnv.Load())
+}
+
+func TestLoad_Directory(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.MkdirAll("conf.d/nested.env", 0o755)
+	_ = os.MkdirAll("empty.d", 0o755)
+	_ = os.WriteFile("conf.d/20-override.env", []byte("DIR_A=override\n"), 0o644)
+	_ = os.WriteFile("conf.d/10-base.env", []byte("DIR_A=base\nDIR_B=base\n"), 0o644)
+	_ = os.WriteFile("conf.d/README", []byte("DIR_C=readme\n"), 0o644)
+	for _, k := range []string{"DIR_A", "DIR_B"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{Precedence: LastWins}, EnvParam: stringSlice{"conf.d/", "empty.d"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "override", os.Getenv("DIR_A"))
+	assert.Equal(t, "base", os.Getenv("DIR_B"))
+	_, ok := os.LookupEnv("DIR_C")
+	assert.False(t, ok)
+	assert.Equal(t, []LoadedKey{
+		{Key: "DIR_A", Source: filepath.Join("conf.d", "20-override.env")},
+		{Key: "DIR_B", Source: filepath.Join("conf.d", "10-base.env")},
+	}, udotEnv.LoadedKeys())
+}
+
+func TestSplitPaths(t *testing.T) {
+	assert.Equal(t, []string{"base.env", "local.env"}, splitPaths("base.env:local.env", ":,"))
+	assert.Equal(t, []string{"a.env", "b.env", "c.env"}, splitPaths("a.env,b.env,,c.env,", ":,"))
+	assert.Equal(t, []string{"a.env", "https://example.com:8443/app.env"}, splitPaths("a.env:https://example.com:8443/app.env", ":,"))
+	assert.Equal(t, []string{"a.env:b.env"}, splitPaths("a.env:b.env", ","))
+	assert.Nil(t, splitPaths("", ":,"))
+}
+
+func TestLoad_PathSeparators(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("base.env", []byte("SEP_A=base\nSEP_B=base\n"), 0o644)
+	_ = os.WriteFile("local.env", []byte("SEP_A=local\n"), 0o644)
+	for _, k := range []string{"SEP_A", "SEP_B"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{Precedence: LastWins, PathSeparators: ":,"}, EnvParam: stringSlice{"base.env:local.env"}}
+	assert.Equal(t, []string{"base.env", "local.env"}, udotEnv.Files())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "local", os.Getenv("SEP_A"))
+	assert.Equal(t, "base", os.Getenv("SEP_B"))
+
+	udotEnv = &UdotEnv{Config: &Config{}, EnvParam: stringSlice{"base.env:local.env"}}
+	assert.ErrorIs(t, udotEnv.Load(), ErrFileNotFound)
+}
+
+func TestLoad_PathEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("a.env", []byte("PATHENV_A=a\n"), 0o644)
+	_ = os.WriteFile("b.env", []byte("PATHENV_A=b\nPATHENV_B=b\n"), 0o644)
+	t.Setenv("UDOTENV_PATH", "a.env,b.env")
+	t.Setenv("PATHENV_B", "env")
+	defer os.Unsetenv("PATHENV_A")
+
+	udotEnv := &UdotEnv{Config: GetDefaultConfig()}
+	assert.Equal(t, []string{"a.env", "b.env"}, udotEnv.Files())
+	assert.False(t, udotEnv.Overload())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "a", os.Getenv("PATHENV_A"))
+	assert.Equal(t, "env", os.Getenv("PATHENV_B"))
+
+	t.Setenv("UDOTENV_OVERLOAD", "true")
+	udotEnv = &UdotEnv{Config: GetDefaultConfig()}
+	assert.True(t, udotEnv.Overload())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "b", os.Getenv("PATHENV_A"))
+	assert.Equal(t, "b", os.Getenv("PATHENV_B"))
+
+	udotEnv = &UdotEnv{Config: GetDefaultConfig(), EnvParam: stringSlice{"a.env"}}
+	assert.Equal(t, []string{"a.env"}, udotEnv.Files())
+
+	udotEnv = &UdotEnv{Config: &Config{}}
+	assert.Empty(t, udotEnv.Files())
+	assert.False(t, udotEnv.Overload())
+}
@@ -0,0 +1,173 @@
+package udotenv
+
+import (
+	"io"
+	"maps"
+	"strings"
+)
+
+// LoadReader loads the env content read from r into the environment, with the
+// same rules as Load: the variables overwrite the environment only with
+// overload, and the values are checked against the config before any of them
+// is set. The `RequiredKeys` are not checked, since they may be provided by
+// another load.
+//
+// Variables loaded from r are kept by later calls to Load, but may be
+// overwritten by the files, e.g. to load defaults generated at runtime before
+// the files:
+//
+//	err := ue.LoadReader(os.Stdin)
+func (ue *UdotEnv) LoadReader(r io.Reader) error {
+	ue.mu.Lock()
+	err := ue.loadReader(r)
+	ue.mu.Unlock()
+	return ue.fail(err)
+}
+
+// LoadString loads the env content s into the environment. See LoadReader.
+func (ue *UdotEnv) LoadString(s string) error {
+	return ue.LoadReader(strings.NewReader(s))
+}
+
+// ParseReader parses the env content read from r and returns its variables
+// without loading them into the environment. Unlike the Parse method, it
+// touches no flags.
+func ParseReader(r io.Reader) (map[string]string, error) {
+	return (&UdotEnv{}).parse("", r)
+}
+
+// Read reads the env files at paths, .env if none is given, and returns
+// their variables without loading them into the environment, like
+// godotenv.Read. Unlike ParseReader, it follows the rules of Load with the
+// default config: the format of each file is detected from its extension,
+// and the first file defining a key wins. Only the given files are read:
+// neither the `.env.defaults` file nor the UDOTENV_PATH and UDOTENV_OVERLOAD
+// variables apply.
+func Read(paths ...string) (map[string]string, error) {
+	if len(paths) == 0 {
+		paths = []string{defaultEnvPath}
+	}
+	return (&UdotEnv{Config: filesConfig()}).Read(paths...)
+}
+
+// filesConfig returns the default config without the `DefaultsFile`,
+// `PathEnv` and `OverloadEnv`, for the helpers that load the files they are
+// given and nothing else.
+func filesConfig() *Config {
+	config := GetDefaultConfig()
+	config.DefaultsFile = ""
+	config.PathEnv = ""
+	config.OverloadEnv = ""
+	return config
+}
+
+// Read reads the env files and returns their variables without loading them
+// into the environment, for applications that pass them on themselves. The
+// files are the ones of Load, with paths replacing the files passed through
+// the env flags if any are given. The precedence, expansion, format, file
+// references and secrets of the config apply, as well as `NormalizeBools`, but the variables
+// are not checked against the environment: every key of the files is
+// returned, whether or not it is already set.
+func (ue *UdotEnv) Read(paths ...string) (map[string]string, error) {
+	ue.mu.Lock()
+	defer ue.mu.Unlock()
+
+	merged, err := ue.read(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string, len(merged))
+	for k, e := range merged {
+		vars[k] = e.value
+	}
+	return vars, nil
+}
+
+// read reads the env files for Read and returns their merged variables, with
+// the file references and the secrets resolved, the templates rendered and the `Transforms` and
+// `NormalizeBools` applied.
+func (ue *UdotEnv) read(paths []string) (map[string]entry, error) {
+	r := ue
+	if len(paths) > 0 {
+		r = &UdotEnv{Config: ue.Config, EnvParam: paths, OverloadParam: ue.OverloadParam}
+	}
+	layers, err := r.fileLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := r.resolve(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string, len(merged))
+	for k, e := range merged {
+		vars[k] = e.value
+	}
+	// as in prepare, before the file references and the secrets
+	if err := r.renderTemplates(merged, vars); err != nil {
+		return nil, err
+	}
+	for k, e := range merged {
+		e.value = vars[k]
+		merged[k] = e
+	}
+	if err := r.readFileRefs(merged); err != nil {
+		return nil, err
+	}
+
+	for k, e := range merged {
+		vars[k] = e.value
+	}
+	if err := r.resolveSecrets(r.loadContext(), vars); err != nil {
+		return nil, err
+	}
+	if err := r.transform(vars); err != nil {
+		return nil, err
+	}
+	if err := r.normalizeBools(vars); err != nil {
+		return nil, err
+	}
+	for k, e := range merged {
+		e.value = vars[k]
+		merged[k] = e
+	}
+	return merged, nil
+}
+
+func (ue *UdotEnv) loadReader(r io.Reader) error {
+	vars, err := ue.parse("", r)
+	if err != nil {
+		return err
+	}
+	return ue.loadLayers([]layer{{vars: vars, overload: ue.Overload()}})
+}
+
+// loadLayers applies layers, which do not come from the files of Load, and
+// adds their variables to the loaded ones.
+func (ue *UdotEnv) loadLayers(layers []layer) error {
+	err := ue.applyLayers(layers)
+	ue.afterLoad(err)
+	return err
+}
+
+// applyLayers implements loadLayers.
+func (ue *UdotEnv) applyLayers(layers []layer) error {
+	merged, err := ue.resolve(layers)
+	if err != nil {
+		return err
+	}
+
+	loaded, err := ue.apply(merged, false)
+	if err != nil {
+		return err
+	}
+
+	if ue.vars == nil {
+		ue.vars = make(map[string]string, len(loaded))
+	}
+	maps.Copy(ue.vars, loaded)
+	return nil
+}
@@ -0,0 +1,120 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadString(t *testing.T) {
+	defer os.Unsetenv("READER_A")
+	defer os.Unsetenv("READER_B")
+	os.Setenv("READER_B", "env")
+
+	udotEnv := &UdotEnv{Config: &Config{}}
+	assert.NoError(t, udotEnv.LoadString("READER_A=1\nREADER_B=2\n"))
+
+	assert.Equal(t, "1", os.Getenv("READER_A"))
+	assert.Equal(t, "env", os.Getenv("READER_B"))
+	v, err := udotEnv.GetString("READER_A")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", v)
+}
+
+func TestLoadReader_ThenLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("READER_FILE=file\n"), 0o644)
+	for _, k := range []string{"READER_DEFAULT", "READER_FILE"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.LoadString("READER_DEFAULT=default\nREADER_FILE=default\n"))
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "default", os.Getenv("READER_DEFAULT"))
+	assert.Equal(t, "file", os.Getenv("READER_FILE"))
+
+	_ = os.WriteFile(path, []byte("\n"), 0o644)
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "default", os.Getenv("READER_DEFAULT"))
+	_, ok := os.LookupEnv("READER_FILE")
+	assert.False(t, ok)
+}
+
+func TestLoadString_Error(t *testing.T) {
+	udotEnv := &UdotEnv{Config: &Config{}}
+	assert.EqualError(t, udotEnv.LoadString("READER_ERR\n"), `line 1, column 1: missing '=' after "READER_ERR"`)
+
+	udotEnv.Config.PanicOnError = true
+	assert.Panics(t, func() { _ = udotEnv.LoadString("READER_ERR\n") })
+}
+
+func TestParseReader(t *testing.T) {
+	vars, err := ParseReader(strings.NewReader("PARSE_A=1\nPARSE_B=\"$PARSE_A two\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"PARSE_A": "1", "PARSE_B": "1 two"}, vars)
+	_, ok := os.LookupEnv("PARSE_A")
+	assert.False(t, ok)
+}
+
+func TestRead(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile(".env", []byte("READ_A=dotenv\nREAD_B=dotenv\n"), 0o644)
+	_ = os.WriteFile("app.json", []byte(`{"read": {"b": "json", "c": true}}`), 0o644)
+	_ = os.WriteFile(".env.defaults", []byte("READ_DEFAULT=defaults\n"), 0o644)
+	t.Setenv("READ_A", "env")
+	t.Setenv("UDOTENV_OVERLOAD", "true")
+
+	vars, err := Read()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"READ_A": "dotenv", "READ_B": "dotenv"}, vars)
+
+	vars, err = Read("app.json", ".env")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"READ_A": "dotenv", "READ_B": "json", "READ_C": "true"}, vars)
+	assert.Equal(t, "env", os.Getenv("READ_A"))
+	_, ok := os.LookupEnv("READ_B")
+	assert.False(t, ok)
+
+	_, err = Read("missing.env")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestUdotEnv_Read(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("flag.env", []byte("READ_HOST=db\nREAD_URL=postgres://${READ_HOST}\n"), 0o644)
+	_ = os.WriteFile("other.env", []byte("READ_HOST=other\n"), 0o644)
+
+	udotEnv := &UdotEnv{Config: &Config{Expand: true}, EnvParam: stringSlice{"flag.env"}}
+	vars, err := udotEnv.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"READ_HOST": "db", "READ_URL": "postgres://db"}, vars)
+
+	vars, err = udotEnv.Read("other.env")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"READ_HOST": "other"}, vars)
+}
+
+func TestUdotEnv_ReadFileRefs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile("password", []byte("s3cret\n"), 0o644)
+	_ = os.WriteFile(".env", []byte("READ_PASSWORD_FILE=password\n"), 0o644)
+	t.Setenv("READ_PASSWORD", "env")
+
+	udotEnv := &UdotEnv{Config: &Config{FileRefSuffix: "_FILE"}}
+	vars, err := udotEnv.Read(".env")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"READ_PASSWORD_FILE": "password", "READ_PASSWORD": "s3cret"}, vars)
+
+	env, err := udotEnv.Environment(".env")
+	assert.NoError(t, err)
+	v, _ := env.Get("READ_PASSWORD")
+	assert.Equal(t, "s3cret", v)
+
+	_ = os.WriteFile(".env", []byte("READ_PASSWORD_FILE=missing\n"), 0o644)
+	_, err = udotEnv.Read(".env")
+	assert.ErrorContains(t, err, "reading READ_PASSWORD_FILE for READ_PASSWORD")
+}
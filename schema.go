@@ -0,0 +1,190 @@
+package udotenv
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type a value must have to satisfy a Rule.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindFloat
+	KindBool
+	KindDuration
+	KindURL
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindDuration:
+		return "duration"
+	case KindURL:
+		return "url"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Rule describes the values allowed for a key.
+//
+// Fields:
+//   - Type: The kind of the value. Numbers, booleans and durations must parse,
+//     URLs must be absolute.
+//   - Required: A boolean indicating whether the key must have a non-empty
+//     value. Rules of keys that are not set are otherwise skipped.
+//   - Pattern: A regular expression the value must match.
+//   - Enum: The list of allowed values, if not empty.
+//   - Min, Max: Bounds for the value of numbers, or for the length of strings.
+type Rule struct {
+	Type     Kind
+	Required bool
+	Pattern  *regexp.Regexp
+	Enum     []string
+	Min, Max *float64
+}
+
+// Schema maps keys to the rules their values must satisfy.
+type Schema map[string]Rule
+
+// Violation is a value that does not satisfy its rule.
+type Violation struct {
+	Key     string
+	Message string
+}
+
+// ValidationError is returned when values do not satisfy a Schema. It lists
+// every violation, sorted by key.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Is makes a *ValidationError match ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Key + ": " + v.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// validate checks the values returned by lookup against the schema. The
+// values are quoted in the messages with quote.
+func (s Schema) validate(lookup func(string) string, quote func(key, value string) string) error {
+	var violations []Violation
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := lookup(key)
+		if msg := s[key].check(value, quote(key, value)); msg != "" {
+			violations = append(violations, Violation{Key: key, Message: msg})
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// check returns a description of why value does not satisfy the rule, or an
+// empty string if it does. The value appears as quoted in the description.
+func (r Rule) check(value, quoted string) string {
+	if value == "" {
+		if r.Required {
+			return "required key is missing"
+		}
+		return ""
+	}
+
+	size := float64(len(value))
+	switch r.Type {
+	case KindInt, KindFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || (r.Type == KindInt && !isInt(value)) {
+			return fmt.Sprintf("%s is not a valid %s", quoted, r.Type)
+		}
+		size = f
+	case KindBool:
+		if _, err := parseBool(value); err != nil {
+			return fmt.Sprintf("%s is not a valid %s", quoted, r.Type)
+		}
+	case KindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Sprintf("%s is not a valid %s", quoted, r.Type)
+		}
+	case KindURL:
+		if u, err := url.Parse(value); err != nil || !u.IsAbs() || u.Host == "" {
+			return fmt.Sprintf("%s is not a valid %s", quoted, r.Type)
+		}
+	}
+
+	if r.Pattern != nil && !r.Pattern.MatchString(value) {
+		return fmt.Sprintf("%s does not match %s", quoted, r.Pattern)
+	}
+	if len(r.Enum) > 0 && !slices.Contains(r.Enum, value) {
+		return fmt.Sprintf("%s is not one of %s", quoted, strings.Join(r.Enum, ", "))
+	}
+
+	what := "value"
+	if r.Type == KindString || r.Type == KindURL {
+		what = "length"
+	}
+	if r.Min != nil && size < *r.Min {
+		return fmt.Sprintf("%s %v is less than %v", what, size, *r.Min)
+	}
+	if r.Max != nil && size > *r.Max {
+		return fmt.Sprintf("%s %v is greater than %v", what, size, *r.Max)
+	}
+	return ""
+}
+
+func isInt(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+// checkSchema validates the environment, as it will be once pending is
+// applied, against the `Schema` of the config.
+func (ue *UdotEnv) checkSchema(pending map[string]string) error {
+	if ue.Config == nil || ue.Config.Schema == nil {
+		return nil
+	}
+
+	return ue.Config.Schema.validate(func(key string) string {
+		if v, ok := pending[key]; ok {
+			return v
+		}
+		return os.Getenv(key)
+	}, ue.quote)
+}
+
+// Validate checks the current environment against schema. If some values do
+// not satisfy their rules, the returned error is a *ValidationError.
+func (ue *UdotEnv) Validate(schema Schema) error {
+	return schema.validate(os.Getenv, ue.quote)
+}
@@ -0,0 +1,90 @@
+package udotenv
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestValidate(t *testing.T) {
+	for k, v := range map[string]string{
+		"SCHEMA_PORT":  "8080",
+		"SCHEMA_MODE":  "dev",
+		"SCHEMA_URL":   "https://example.com",
+		"SCHEMA_RATIO": "0.5",
+		"SCHEMA_TTL":   "5m",
+		"SCHEMA_DEBUG": "on",
+		"SCHEMA_NAME":  "app",
+	} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{}
+	err := udotEnv.Validate(Schema{
+		"SCHEMA_PORT":  {Type: KindInt, Min: float(1), Max: float(65535)},
+		"SCHEMA_MODE":  {Enum: []string{"dev", "prod"}},
+		"SCHEMA_URL":   {Type: KindURL, Required: true},
+		"SCHEMA_RATIO": {Type: KindFloat, Max: float(1)},
+		"SCHEMA_TTL":   {Type: KindDuration},
+		"SCHEMA_DEBUG": {Type: KindBool},
+		"SCHEMA_NAME":  {Pattern: regexp.MustCompile(`^[a-z]+$`), Min: float(2)},
+		"SCHEMA_UNSET": {Type: KindInt},
+	})
+	assert.NoError(t, err)
+}
+
+func TestValidate_Violations(t *testing.T) {
+	for k, v := range map[string]string{
+		"SCHEMA_BAD_PORT": "http",
+		"SCHEMA_BAD_MODE": "test",
+		"SCHEMA_BAD_URL":  "example.com",
+		"SCHEMA_BAD_MAX":  "70000",
+		"SCHEMA_BAD_NAME": "App",
+	} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{}
+	err := udotEnv.Validate(Schema{
+		"SCHEMA_BAD_PORT":    {Type: KindInt},
+		"SCHEMA_BAD_MODE":    {Enum: []string{"dev", "prod"}},
+		"SCHEMA_BAD_URL":     {Type: KindURL},
+		"SCHEMA_BAD_MAX":     {Type: KindInt, Max: float(65535)},
+		"SCHEMA_BAD_NAME":    {Pattern: regexp.MustCompile(`^[a-z]+$`)},
+		"SCHEMA_BAD_MISSING": {Required: true},
+	})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []Violation{
+		{Key: "SCHEMA_BAD_MAX", Message: "value 70000 is greater than 65535"},
+		{Key: "SCHEMA_BAD_MISSING", Message: "required key is missing"},
+		{Key: "SCHEMA_BAD_MODE", Message: `"test" is not one of dev, prod`},
+		{Key: "SCHEMA_BAD_NAME", Message: `"App" does not match ^[a-z]+$`},
+		{Key: "SCHEMA_BAD_PORT", Message: `"http" is not a valid int`},
+		{Key: "SCHEMA_BAD_URL", Message: `"example.com" is not a valid url`},
+	}, validationErr.Violations)
+}
+
+func TestLoad_Schema(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("SCHEMA_LOAD_PORT=http\n"), 0o644)
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{Schema: Schema{"SCHEMA_LOAD_PORT": {Type: KindInt}}},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.EqualError(t, udotEnv.Load(), `validation failed: SCHEMA_LOAD_PORT: "http" is not a valid int`)
+	_, ok := os.LookupEnv("SCHEMA_LOAD_PORT")
+	assert.False(t, ok)
+}
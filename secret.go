@@ -0,0 +1,40 @@
+package udotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const defaultSecretScheme = "secret://"
+
+// resolveSecrets replaces the secret references among vars with the values
+// returned by the `SecretResolver` of the config. All the references are
+// resolved before an error is returned, so that it names every failing key.
+// The references themselves are never part of the error.
+func (ue *UdotEnv) resolveSecrets(ctx context.Context, vars map[string]string) error {
+	if ue.Config == nil || ue.Config.SecretResolver == nil {
+		return nil
+	}
+
+	scheme := ue.Config.SecretScheme
+	if scheme == "" {
+		scheme = defaultSecretScheme
+	}
+
+	var errs []error
+	for _, k := range sortedKeys(vars) {
+		if !strings.HasPrefix(vars[k], scheme) {
+			continue
+		}
+
+		v, err := ue.Config.SecretResolver(ctx, vars[k])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolving secret for %s: %w", k, err))
+			continue
+		}
+		vars[k] = v
+	}
+	return errors.Join(errs...)
+}
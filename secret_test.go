@@ -0,0 +1,52 @@
+package udotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_SecretResolver(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("SECRET_PASSWORD=secret://prod/db\nSECRET_PLAIN=plain\n"), 0o644)
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("SECRET_PASSWORD")
+	defer os.Unsetenv("SECRET_PLAIN")
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			SecretResolver: func(ctx context.Context, ref string) (string, error) {
+				assert.Equal(t, "secret://prod/db", ref)
+				return "hunter2", nil
+			},
+		},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "hunter2", os.Getenv("SECRET_PASSWORD"))
+	assert.Equal(t, "plain", os.Getenv("SECRET_PLAIN"))
+}
+
+func TestLoad_SecretResolverErrors(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("SECRET_A=vault:a\nSECRET_B=vault:b\n"), 0o644)
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			SecretScheme: "vault:",
+			SecretResolver: func(ctx context.Context, ref string) (string, error) {
+				return "", errors.New("access denied")
+			},
+		},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.EqualError(t, udotEnv.Load(), "resolving secret for SECRET_A: access denied\nresolving secret for SECRET_B: access denied")
+
+	_, ok := os.LookupEnv("SECRET_A")
+	assert.False(t, ok)
+}
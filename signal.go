@@ -0,0 +1,50 @@
+package udotenv
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Reload loads the env files again, even if they did not change, and returns
+// the changed variables. Like Load, it updates or removes the variables set
+// by previous loads, while variables that were in the environment beforehand
+// are only overwritten with overload. A failed reload leaves the environment
+// untouched, unless `ContinueOnError` is set.
+func (ue *UdotEnv) Reload() (map[string]Change, error) {
+	ue.mu.Lock()
+	defer ue.mu.Unlock()
+	return ue.load()
+}
+
+// ReloadOnSignal reloads the env files each time one of sigs is received,
+// conventionally syscall.SIGHUP, and calls `Config.OnReload` with the result.
+// The returned function stops listening for the signals, and may be called
+// several times.
+func (ue *UdotEnv) ReloadOnSignal(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				changed, err := ue.Reload()
+				if ue.Config != nil && ue.Config.OnReload != nil {
+					ue.Config.OnReload(changed, err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+//go:build unix
+
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("SIGNAL_A=1\n"), 0o644)
+	defer os.Unsetenv("SIGNAL_A")
+
+	reloaded := make(chan map[string]Change, 1)
+	udotEnv := &UdotEnv{
+		Config: &Config{OnReload: func(changed map[string]Change, err error) {
+			assert.NoError(t, err)
+			reloaded <- changed
+		}},
+		EnvParam: stringSlice{path},
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	stop := udotEnv.ReloadOnSignal(syscall.SIGHUP)
+	defer stop()
+
+	_ = os.WriteFile(path, []byte("SIGNAL_A=2\n"), 0o644)
+	_ = syscall.Kill(os.Getpid(), syscall.SIGHUP)
+
+	select {
+	case changed := <-reloaded:
+		assert.Equal(t, map[string]Change{"SIGNAL_A": {Kind: Modified, Old: "1", New: "2"}}, changed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload")
+	}
+	assert.Equal(t, "2", os.Getenv("SIGNAL_A"))
+}
+
+func TestReloadOnSignal_StopTwice(t *testing.T) {
+	udotEnv := &UdotEnv{Config: &Config{}}
+	stop := udotEnv.ReloadOnSignal(syscall.SIGHUP)
+	stop()
+	assert.NotPanics(t, stop)
+}
+
+func TestReload_Error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("RELOAD_ERR=1\n"), 0o644)
+	defer os.Unsetenv("RELOAD_ERR")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	_ = os.WriteFile(path, []byte("RELOAD_ERR\n"), 0o644)
+	_, err := udotEnv.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "1", os.Getenv("RELOAD_ERR"))
+}
@@ -0,0 +1,85 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// EnvSnapshot is a copy of the process environment taken by Snapshot.
+type EnvSnapshot struct {
+	vars map[string]string
+}
+
+// Snapshot captures the whole process environment, so that the changes made
+// by Load and the like can be reverted with Restore:
+//
+//	snap := udotenv.Snapshot()
+//	defer snap.Restore()
+func Snapshot() *EnvSnapshot {
+	return &EnvSnapshot{vars: environ()}
+}
+
+// Vars returns a copy of the variables of the snapshot.
+func (s *EnvSnapshot) Vars() map[string]string {
+	return maps.Clone(s.vars)
+}
+
+// Restore reverts the process environment to the snapshot: the variables set
+// since are unset, and the changed or unset ones get their previous value
+// back. Every variable is restored before an error is returned.
+func (s *EnvSnapshot) Restore() error {
+	var errs []error
+	for k := range environ() {
+		if _, ok := s.vars[k]; !ok {
+			if err := os.Unsetenv(k); err != nil {
+				errs = append(errs, fmt.Errorf("unsetting %s: %w", k, err))
+			}
+		}
+	}
+	for _, k := range sortedKeys(s.vars) {
+		if v, ok := os.LookupEnv(k); ok && v == s.vars[k] {
+			continue
+		}
+		if err := os.Setenv(k, s.vars[k]); err != nil {
+			errs = append(errs, fmt.Errorf("setting %s: %w", k, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// environ returns the process environment as a map.
+func environ() map[string]string {
+	env := os.Environ()
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		// the hidden "=C:" variables of Windows have no name
+		if k, v, ok := strings.Cut(kv, "="); ok && k != "" {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// WithEnv loads the env files, with the default config, runs fn and restores
+// the environment as it was before, even if fn panics. Like Read, it loads
+// the given files only, without the `.env.defaults` file. It returns the error
+// of the load, in which case fn is not run, or the one of fn.
+//
+//	err := udotenv.WithEnv([]string{".env.migrate"}, migrate)
+func WithEnv(files []string, fn func() error) (err error) {
+	snap := Snapshot()
+	defer func() {
+		if rerr := snap.Restore(); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+	}()
+
+	ue := &UdotEnv{Config: filesConfig(), EnvParam: files}
+	if err := ue.Load(); err != nil {
+		return err
+	}
+	return fn()
+}
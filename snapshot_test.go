@@ -0,0 +1,66 @@
+package udotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot_Restore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("SNAP_NEW=1\nSNAP_CHANGED=file\n"), 0o644)
+	t.Setenv("SNAP_CHANGED", "env")
+	t.Setenv("SNAP_REMOVED", "env")
+
+	snap := Snapshot()
+	assert.Equal(t, "env", snap.Vars()["SNAP_CHANGED"])
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}, OverloadParam: true}
+	assert.NoError(t, udotEnv.Load())
+	os.Unsetenv("SNAP_REMOVED")
+	assert.Equal(t, "file", os.Getenv("SNAP_CHANGED"))
+
+	assert.NoError(t, snap.Restore())
+	_, ok := os.LookupEnv("SNAP_NEW")
+	assert.False(t, ok)
+	assert.Equal(t, "env", os.Getenv("SNAP_CHANGED"))
+	assert.Equal(t, "env", os.Getenv("SNAP_REMOVED"))
+	assert.Equal(t, snap.Vars(), Snapshot().Vars())
+}
+
+func TestWithEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile(".env.defaults", []byte("WITHENV_DEFAULT=1\n"), 0o644)
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("WITHENV_A=1\n"), 0o644)
+
+	err := WithEnv([]string{path}, func() error {
+		assert.Equal(t, "1", os.Getenv("WITHENV_A"))
+		_, ok := os.LookupEnv("WITHENV_DEFAULT")
+		assert.False(t, ok)
+		os.Setenv("WITHENV_B", "set by fn")
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	_, ok := os.LookupEnv("WITHENV_A")
+	assert.False(t, ok)
+	_, ok = os.LookupEnv("WITHENV_B")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		_ = WithEnv([]string{path}, func() error { panic("boom") })
+	})
+	_, ok = os.LookupEnv("WITHENV_A")
+	assert.False(t, ok)
+
+	called := false
+	err = WithEnv([]string{filepath.Join(t.TempDir(), "missing.env")}, func() error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrFileNotFound)
+	assert.False(t, called)
+}
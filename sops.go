@@ -0,0 +1,55 @@
+package udotenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+// sopsMetadataRegex matches the metadata that SOPS adds to the env files it
+// encrypts.
+var sopsMetadataRegex = regexp.MustCompile(`(?m)^sops_(version|mac)=`)
+
+// isSOPS reports whether src is an env file encrypted with SOPS.
+func isSOPS(src []byte) bool {
+	return sopsMetadataRegex.Match(src)
+}
+
+// decryptSOPS decrypts an env file encrypted with SOPS, with the
+// `SOPSDecrypt` function of the config or the sops binary.
+func (ue *UdotEnv) decryptSOPS(src []byte) ([]byte, error) {
+	decrypt := runSOPS
+	if ue.Config != nil && ue.Config.SOPSDecrypt != nil {
+		decrypt = ue.Config.SOPSDecrypt
+	}
+
+	plain, err := decrypt(ue.loadContext(), src)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting SOPS file: %w", err)
+	}
+	return plain, nil
+}
+
+// runSOPS decrypts src with the sops binary found in PATH, which picks the
+// key from the metadata of the file: age, PGP, or a cloud KMS.
+func runSOPS(ctx context.Context, src []byte) ([]byte, error) {
+	path, err := exec.LookPath("sops")
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "--decrypt", "--input-type", "dotenv", "--output-type", "dotenv", "/dev/stdin")
+	cmd.Stdin = bytes.NewReader(src)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package udotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sopsFile = `SOPS_A=ENC[AES256_GCM,data:Zm9v,iv:aXY=,tag:dGFn,type:str]
+sops_version=3.9.0
+sops_mac=ENC[AES256_GCM,data:bWFj,iv:aXY=,tag:dGFn,type:str]
+sops_lastmodified=2024-01-01T00:00:00Z
+`
+
+func TestLoad_SOPS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte(sopsFile), 0o644)
+	defer os.Unsetenv("SOPS_A")
+
+	var encrypted []byte
+	udotEnv := &UdotEnv{
+		Config: &Config{SOPSDecrypt: func(ctx context.Context, src []byte) ([]byte, error) {
+			encrypted = src
+			return []byte("SOPS_A=decrypted\n"), nil
+		}},
+		EnvParam: stringSlice{path},
+	}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, sopsFile, string(encrypted))
+	assert.Equal(t, "decrypted", os.Getenv("SOPS_A"))
+	_, ok := os.LookupEnv("sops_mac")
+	assert.False(t, ok)
+}
+
+func TestLoad_SOPSBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the sops binary")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\n[ \"$1\" = --decrypt ] || exit 1\necho 'decrypting failed' >&2\nexit 128\n"
+	_ = os.WriteFile(filepath.Join(dir, "sops"), []byte(script), 0o755)
+	_ = os.WriteFile(filepath.Join(dir, ".env"), []byte(sopsFile), 0o644)
+	t.Setenv("PATH", dir)
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{filepath.Join(dir, ".env")}}
+	assert.EqualError(t, udotEnv.Load(), "error loading file '"+filepath.Join(dir, ".env")+
+		"': decrypting SOPS file: exit status 128: decrypting failed")
+}
+
+func TestIsSOPS(t *testing.T) {
+	assert.True(t, isSOPS([]byte(sopsFile)))
+	assert.False(t, isSOPS([]byte("A=1\nMY_sops_version=1\n")))
+}
@@ -0,0 +1,79 @@
+package udotenv
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Source is a source of variables other than an env file, e.g. a secret
+// store or a configuration service. Sources are added to `Config.Sources`,
+// or registered for a scheme with RegisterScheme so that their references
+// can be passed through the env flags like files.
+type Source interface {
+	// Fetch returns the variables of the source.
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// SourceFunc is an adapter to use an ordinary function as a Source.
+type SourceFunc func(ctx context.Context) (map[string]string, error)
+
+// Fetch calls f(ctx).
+func (f SourceFunc) Fetch(ctx context.Context) (map[string]string, error) {
+	return f(ctx)
+}
+
+// SourceFactory creates the Source of a reference such as
+// "consul://myapp/prod".
+type SourceFactory func(ref string) (Source, error)
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = make(map[string]SourceFactory)
+)
+
+// RegisterScheme makes the sources created by factory available for the
+// references starting with "scheme://" passed through the env flags. It is
+// meant to be called from the init function of the package providing the
+// source, and panics if the scheme is registered twice or factory is nil.
+func RegisterScheme(scheme string, factory SourceFactory) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+
+	if factory == nil {
+		panic("udotenv: RegisterScheme factory is nil")
+	}
+	if _, dup := schemes[scheme]; dup {
+		panic("udotenv: RegisterScheme called twice for scheme " + scheme)
+	}
+	schemes[scheme] = factory
+}
+
+// lookupScheme returns the factory registered for the scheme of ref, if any.
+func lookupScheme(ref string) (SourceFactory, bool) {
+	scheme, _, ok := strings.Cut(ref, "://")
+	if !ok {
+		return nil, false
+	}
+
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	factory, ok := schemes[scheme]
+	return factory, ok
+}
+
+// fetchScheme fetches the variables of the source created by factory for ref.
+func fetchScheme(ctx context.Context, factory SourceFactory, ref string) (map[string]string, error) {
+	src, err := factory(ref)
+	if err != nil {
+		return nil, err
+	}
+	return src.Fetch(ctx)
+}
+
+// isRemote reports whether path is not a local file but a URL or the
+// reference of a registered scheme.
+func isRemote(path string) bool {
+	_, ok := lookupScheme(path)
+	return ok || isURL(path)
+}
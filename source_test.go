@@ -0,0 +1,57 @@
+package udotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Sources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("SOURCE_A=file\nSOURCE_B=file\n"), 0o644)
+	for _, k := range []string{"SOURCE_A", "SOURCE_B"} {
+		defer os.Unsetenv(k)
+	}
+
+	src := SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"SOURCE_B": "source"}, nil
+	})
+	udotEnv := &UdotEnv{
+		Config:   &Config{Precedence: LastWins, Sources: []Source{src}},
+		EnvParam: stringSlice{path},
+	}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "file", os.Getenv("SOURCE_A"))
+	assert.Equal(t, "source", os.Getenv("SOURCE_B"))
+}
+
+func TestLoad_SourceError(t *testing.T) {
+	src := SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		return nil, errors.New("unavailable")
+	})
+	udotEnv := &UdotEnv{Config: &Config{Sources: []Source{src}}}
+	assert.EqualError(t, udotEnv.Load(), "error loading source 0: unavailable")
+}
+
+func TestRegisterScheme(t *testing.T) {
+	defer os.Unsetenv("SCHEME_A")
+
+	RegisterScheme("test-scheme", func(ref string) (Source, error) {
+		return SourceFunc(func(ctx context.Context) (map[string]string, error) {
+			return map[string]string{"SCHEME_A": ref}, nil
+		}), nil
+	})
+	assert.Panics(t, func() { RegisterScheme("test-scheme", nil) })
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{"test-scheme://app"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "test-scheme://app", os.Getenv("SCHEME_A"))
+
+	changed, err := udotEnv.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
@@ -0,0 +1,120 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// KeysOf returns the keys bound by the `env` tags of the struct pointed to by
+// target, nested structs included, as Bind would read them. It panics if
+// target is not a pointer to a struct. Its result is meant for
+// `Config.KnownKeys`:
+//
+//	config.KnownKeys = udotenv.KeysOf(&AppConfig{})
+func KeysOf(target any) []string {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		panic("udotenv: KeysOf target must be a pointer to a struct")
+	}
+	return structKeys(t.Elem(), "", nil)
+}
+
+func structKeys(t reflect.Type, prefix string, keys []string) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok {
+			if isNestedStruct(field.Type) {
+				keys = structKeys(field.Type, prefix+field.Tag.Get("envPrefix"), keys)
+			}
+			continue
+		}
+		keys = append(keys, prefix+parseFieldTag(tag).name)
+	}
+	return keys
+}
+
+// checkStrict verifies, if `Strict` is set in the config, that every merged
+// key is known: one of the `KnownKeys`, the `RequiredKeys` or the keys of the
+// `Schema`, or a file reference to one of them.
+func (ue *UdotEnv) checkStrict(merged map[string]entry) error {
+	if ue.Config == nil || !ue.Config.Strict {
+		return nil
+	}
+
+	known := ue.knownKeys()
+	var errs []error
+	for _, k := range sortedKeys(merged) {
+		if known[k] {
+			continue
+		}
+		if suffix := ue.Config.FileRefSuffix; suffix != "" && known[strings.TrimSuffix(k, suffix)] {
+			continue
+		}
+
+		msg := fmt.Sprintf("unknown key %s", k)
+		if e := merged[k]; e.source != "" {
+			msg = fmt.Sprintf("%s: %s", e.source, msg)
+		}
+		if guess := closestKey(k, known); guess != "" {
+			msg = fmt.Sprintf("%s, did you mean %s?", msg, guess)
+		}
+		errs = append(errs, errors.New(msg))
+	}
+	return withSentinel(errors.Join(errs...), ErrValidation)
+}
+
+// knownKeys returns the keys declared by the config: the `KnownKeys`, the
+// `RequiredKeys` and the keys of the `Schema`.
+func (ue *UdotEnv) knownKeys() map[string]bool {
+	known := make(map[string]bool, len(ue.Config.KnownKeys)+len(ue.Config.RequiredKeys)+len(ue.Config.Schema))
+	for _, k := range ue.Config.KnownKeys {
+		known[k] = true
+	}
+	for _, k := range ue.Config.RequiredKeys {
+		known[k] = true
+	}
+	for k := range ue.Config.Schema {
+		known[k] = true
+	}
+	return known
+}
+
+// closestKey returns the known key the closest to k, if it is within two
+// edits of it.
+func closestKey(k string, known map[string]bool) string {
+	best, bestDist := "", 3
+	for _, candidate := range sortedKeys(known) {
+		if d := editDistance(k, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
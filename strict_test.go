@@ -0,0 +1,59 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysOf(t *testing.T) {
+	type config struct {
+		Port  int    `env:"PORT,default=8080"`
+		DSN   string `env:"DATABASE_URL,required"`
+		Cache struct {
+			Host string `env:"HOST"`
+		} `envPrefix:"CACHE_"`
+		ignored string `env:"IGNORED"`
+	}
+
+	assert.Equal(t, []string{"PORT", "DATABASE_URL", "CACHE_HOST"}, KeysOf(&config{}))
+	assert.Panics(t, func() { KeysOf(config{}) })
+}
+
+func TestLoad_Strict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("STRICT_PORT=8080\nSTRICT_DATABSE_URL=postgres://db\nSTRICT_TOKEN_FILE=/run/token\nSTRICT_EXTRA=1\n"), 0o644)
+	defer os.Unsetenv("STRICT_PORT")
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			Strict:        true,
+			KnownKeys:     []string{"STRICT_PORT", "STRICT_DATABASE_URL"},
+			Schema:        Schema{"STRICT_TOKEN": {}},
+			FileRefSuffix: "_FILE",
+		},
+		EnvParam: stringSlice{path},
+	}
+	err := udotEnv.Load()
+	assert.EqualError(t, err, path+": unknown key STRICT_DATABSE_URL, did you mean STRICT_DATABASE_URL?\n"+
+		path+": unknown key STRICT_EXTRA")
+	assert.ErrorIs(t, err, ErrValidation)
+	_, ok := os.LookupEnv("STRICT_PORT")
+	assert.False(t, ok)
+
+	udotEnv.Config.Strict = false
+	udotEnv.Config.FileRefSuffix = ""
+	assert.NoError(t, udotEnv.Load())
+	for _, k := range []string{"STRICT_DATABSE_URL", "STRICT_TOKEN_FILE", "STRICT_EXTRA"} {
+		os.Unsetenv(k)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("abc", "abc"))
+	assert.Equal(t, 1, editDistance("DATABSE_URL", "DATABASE_URL"))
+	assert.Equal(t, 2, editDistance("PROT", "PORT"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
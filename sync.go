@@ -0,0 +1,46 @@
+package udotenv
+
+import (
+	"os"
+)
+
+// readDocument parses the env file at path, keeping its layout.
+func readDocument(path string) (*document, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocument(src)
+}
+
+// SyncFile writes the current values of the environment back into the env
+// file at path. Every key of the file gets the value it currently has in the
+// environment, while comments, blank lines and the order of the keys are kept.
+// Keys that are not set in the environment keep their values from the file,
+// and variables that are not in the file are not added.
+func (ue *UdotEnv) SyncFile(path string) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	vars := make(map[string]string, len(doc.statements))
+	current := make([]string, len(doc.statements))
+	for i, st := range doc.statements {
+		if st.quote != singleQuote {
+			st.value = expandVariables(st.value, vars)
+		}
+		vars[st.key] = st.value
+		current[i] = st.value
+	}
+
+	// statements are replaced from the end, so that the line numbers of the
+	// ones before stay valid when a multi-line value shrinks
+	for i := len(doc.statements) - 1; i >= 0; i-- {
+		st := doc.statements[i]
+		if v, ok := os.LookupEnv(st.key); ok && v != current[i] {
+			doc.setValue(st, v)
+		}
+	}
+	return writeFileAtomic(path, doc.bytes())
+}
@@ -0,0 +1,48 @@
+package udotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncFile(t *testing.T) {
+	content := "# database\n" +
+		"export SYNC_HOST=localhost # local only\n" +
+		"\n" +
+		"SYNC_PASSWORD='multi\nline'\n" +
+		"SYNC_UNSET=keep\n" +
+		"SYNC_SAME=\"same value\"\n"
+	_ = os.WriteFile(".test.env", []byte(content), 0o644)
+	defer os.Remove(".test.env")
+
+	os.Setenv("SYNC_HOST", "db.internal")
+	defer os.Unsetenv("SYNC_HOST")
+	os.Setenv("SYNC_PASSWORD", `it's "secret"`)
+	defer os.Unsetenv("SYNC_PASSWORD")
+	os.Setenv("SYNC_SAME", "same value")
+	defer os.Unsetenv("SYNC_SAME")
+
+	udotEnv := &UdotEnv{}
+	assert.NoError(t, udotEnv.SyncFile(".test.env"))
+
+	result, err := os.ReadFile(".test.env")
+	assert.NoError(t, err)
+	assert.Equal(t, "# database\n"+
+		"export SYNC_HOST=db.internal # local only\n"+
+		"\n"+
+		"SYNC_PASSWORD=\"it's \\\"secret\\\"\"\n"+
+		"SYNC_UNSET=keep\n"+
+		"SYNC_SAME=\"same value\"\n", string(result))
+
+	doc, err := readDocument(".test.env")
+	assert.NoError(t, err)
+	assert.Equal(t, `it's "secret"`, doc.vars()["SYNC_PASSWORD"])
+}
+
+func TestSyncFile_Missing(t *testing.T) {
+	udotEnv := &UdotEnv{}
+
+	assert.Error(t, udotEnv.SyncFile(".missing.env"))
+}
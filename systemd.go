@@ -0,0 +1,178 @@
+package udotenv
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var systemdKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type systemdState int
+
+const (
+	systemdPreKey systemdState = iota
+	systemdKey
+	systemdPreValue
+	systemdValue
+	systemdValueEscape
+	systemdSingleQuote
+	systemdDoubleQuote
+	systemdDoubleQuoteEscape
+	systemdComment
+	systemdCommentEscape
+)
+
+// parseSystemd parses src with the semantics of the EnvironmentFile= files of
+// systemd:
+//   - lines starting with `#` or `;` are comments, and `#` elsewhere is part
+//     of the value;
+//   - the separator is `=`, and there is no `export` prefix;
+//   - unquoted values are trimmed, and a backslash escapes the next
+//     character or continues the value on the next line;
+//   - single-quoted text is literal, and in double-quoted text a backslash
+//     only escapes `"`, `\`, `$`, backquotes and line breaks;
+//   - quotes are only special at the start of a value, or right after a
+//     closing quote, so that `A="b c"'d'` is "b cd" while `A=b"c"` is
+//     literal;
+//   - values are never expanded, and `%` is literal since specifiers only
+//     apply to Environment= settings in the unit itself.
+//
+// Where systemd ignores a line with a warning, such as a line without `=`, an
+// invalid variable name or an unterminated quote, parseSystemd fails, so that
+// the divergence is noticed.
+func parseSystemd(src []byte) (map[string]string, error) {
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	vars := make(map[string]string)
+
+	var key, value strings.Builder
+	var keep, line, col, keyLine, keyCol, quoteLine, quoteCol int
+	keyErr := func(format string) error {
+		k := strings.TrimSpace(key.String())
+		return &ParseError{Line: keyLine, Column: keyCol, Text: k, Msg: fmt.Sprintf(format, k)}
+	}
+	assign := func() error {
+		k := strings.TrimRightFunc(key.String(), isSpace)
+		if !systemdKeyRegex.MatchString(k) {
+			return keyErr("invalid variable name %q")
+		}
+		vars[k] = value.String()[:keep]
+		key.Reset()
+		value.Reset()
+		keep = 0
+		return nil
+	}
+
+	line = 1
+	state := systemdPreKey
+	for _, c := range src {
+		col++
+		switch state {
+		case systemdPreKey:
+			switch {
+			case c == '#' || c == ';':
+				state = systemdComment
+			case c == '\n':
+			case isSpace(rune(c)):
+			default:
+				state = systemdKey
+				keyLine, keyCol = line, col
+				key.WriteByte(c)
+			}
+		case systemdKey:
+			switch c {
+			case '\n':
+				return nil, keyErr("missing '=' after %q")
+			case '=':
+				state = systemdPreValue
+			default:
+				key.WriteByte(c)
+			}
+		case systemdPreValue, systemdValue:
+			switch {
+			case c == '\n':
+				if err := assign(); err != nil {
+					return nil, err
+				}
+				state = systemdPreKey
+			case c == '\'' && state == systemdPreValue:
+				state = systemdSingleQuote
+				quoteLine, quoteCol = line, col
+			case c == '"' && state == systemdPreValue:
+				state = systemdDoubleQuote
+				quoteLine, quoteCol = line, col
+			case c == '\\':
+				state = systemdValueEscape
+			case isSpace(rune(c)) && state == systemdPreValue:
+			default:
+				state = systemdValue
+				value.WriteByte(c)
+				if !isSpace(rune(c)) {
+					keep = value.Len()
+				}
+			}
+		case systemdValueEscape:
+			state = systemdValue
+			if c != '\n' {
+				value.WriteByte(c)
+				keep = value.Len()
+			}
+		case systemdSingleQuote:
+			if c == '\'' {
+				state = systemdPreValue
+			} else {
+				value.WriteByte(c)
+				keep = value.Len()
+			}
+		case systemdDoubleQuote:
+			switch c {
+			case '"':
+				state = systemdPreValue
+			case '\\':
+				state = systemdDoubleQuoteEscape
+			default:
+				value.WriteByte(c)
+				keep = value.Len()
+			}
+		case systemdDoubleQuoteEscape:
+			state = systemdDoubleQuote
+			switch c {
+			case '"', '\\', '$', '`':
+				value.WriteByte(c)
+			case '\n':
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(c)
+			}
+			keep = value.Len()
+		case systemdComment:
+			switch c {
+			case '\\':
+				state = systemdCommentEscape
+			case '\n':
+				state = systemdPreKey
+			}
+		case systemdCommentEscape:
+			state = systemdComment
+		}
+		if c == '\n' {
+			line++
+			col = 0
+		}
+	}
+
+	switch state {
+	case systemdKey:
+		return nil, keyErr("missing '=' after %q")
+	case systemdSingleQuote, systemdDoubleQuote, systemdDoubleQuoteEscape:
+		lines := strings.Split(string(src), "\n")
+		return nil, &ParseError{Line: quoteLine, Column: quoteCol, Text: lines[quoteLine-1][quoteCol-1:],
+			Msg: fmt.Sprintf("unterminated quoted value for %s", strings.TrimSpace(key.String()))}
+	case systemdPreValue, systemdValue, systemdValueEscape:
+		if err := assign(); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
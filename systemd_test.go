@@ -0,0 +1,73 @@
+package udotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSystemd(t *testing.T) {
+	src := "# comment\n" +
+		"; also a comment \\\n" +
+		"  continued comment\n" +
+		"\n" +
+		"PLAIN = value with spaces   \n" +
+		"HASH=a #b\n" +
+		"PERCENT=100%n\n" +
+		"SINGLE='$HOME \\n'\n" +
+		"DOUBLE=\"a \\\"b\\\" \\$c \\n \\\\\"\n" +
+		"JOINED=\"b c\"'d' e\n" +
+		"INNER=b\"c\"\n" +
+		"ESCAPED=a\\ \\#b\\\n" +
+		"  c\n" +
+		"MULTI=\"first\n" +
+		"second\"\n" +
+		"EMPTY=\n" +
+		"REF=$PLAIN\r\n" +
+		"LAST=end"
+
+	vars, err := parseSystemd([]byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"PLAIN":   "value with spaces",
+		"HASH":    "a #b",
+		"PERCENT": "100%n",
+		"SINGLE":  `$HOME \n`,
+		"DOUBLE":  `a "b" $c \n \`,
+		"JOINED":  "b cde",
+		"INNER":   `b"c"`,
+		"ESCAPED": "a #b  c",
+		"MULTI":   "first\nsecond",
+		"EMPTY":   "",
+		"REF":     "$PLAIN",
+		"LAST":    "end",
+	}, vars)
+}
+
+func TestParseSystemd_Errors(t *testing.T) {
+	tests := map[string]string{
+		"A=1\nexport B=2\n": `line 2, column 1: invalid variable name "export B"`,
+		"A=1\n\nB\n":        `line 3, column 1: missing '=' after "B"`,
+		"A=1\nB:2":          `line 2, column 1: missing '=' after "B:2"`,
+		"A=1\nB=\"open\n":   "line 2, column 3: unterminated quoted value for B",
+		"1A=1\n":            `line 1, column 1: invalid variable name "1A"`,
+	}
+	for src, msg := range tests {
+		_, err := parseSystemd([]byte(src))
+		assert.EqualError(t, err, msg, src)
+	}
+}
+
+func TestLoad_DialectSystemd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.env")
+	_ = os.WriteFile(path, []byte("SYSTEMD_URL=http://host/%i#frag\nSYSTEMD_REF=${SYSTEMD_URL}\n"), 0o644)
+	defer os.Unsetenv("SYSTEMD_URL")
+	defer os.Unsetenv("SYSTEMD_REF")
+
+	udotEnv := &UdotEnv{Config: &Config{Dialect: DialectSystemd, Expand: true}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "http://host/%i#frag", os.Getenv("SYSTEMD_URL"))
+	assert.Equal(t, "${SYSTEMD_URL}", os.Getenv("SYSTEMD_REF"))
+}
@@ -0,0 +1,156 @@
+package udotenv
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// renderTemplates renders the values of vars containing `{{` as Go
+// templates when `Templates` is set in the config. The data of the templates
+// is the environment as it will be once vars is set, so that
+// `{{.PGUSER}}` refers to the value of PGUSER whether it comes from the files
+// or from the process. A template referring to another one is rendered after
+// it, and a reference cycle is an error. The values marked as literal in
+// merged, which do not come from env content, are left as they are.
+func (ue *UdotEnv) renderTemplates(merged map[string]entry, vars map[string]string) error {
+	if ue.Config == nil || !ue.Config.Templates {
+		return nil
+	}
+
+	missingKey := "zero"
+	if ue.Config.TemplateMissingKeyError {
+		missingKey = "error"
+	}
+	r := &renderer{
+		vars:      vars,
+		data:      make(map[string]string),
+		templates: make(map[string]*template.Template),
+		rendered:  make(map[string]bool),
+	}
+	for _, k := range sortedKeys(vars) {
+		if merged[k].literal || !strings.Contains(vars[k], "{{") {
+			continue
+		}
+		tmpl, err := template.New(k).Option("missingkey=" + missingKey).Parse(vars[k])
+		if err != nil {
+			return withSentinel(fmt.Errorf("rendering %s: %w", k, err), ErrParse)
+		}
+		r.templates[k] = tmpl
+	}
+	if len(r.templates) == 0 {
+		return nil
+	}
+
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			r.data[k] = v
+		}
+	}
+	for k, v := range vars {
+		r.data[k] = v
+	}
+	for _, k := range sortedKeys(r.templates) {
+		if err := r.render(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderer renders the templates of the variables in dependency order.
+type renderer struct {
+	vars      map[string]string
+	data      map[string]string
+	templates map[string]*template.Template
+	rendered  map[string]bool
+	visiting  []string // keys being rendered, to detect cycles
+}
+
+// render renders the template of the variable k, once the templates it refers
+// to are rendered.
+func (r *renderer) render(k string) error {
+	if r.rendered[k] {
+		return nil
+	}
+	for i, key := range r.visiting {
+		if key == k {
+			cycle := append(slices.Clone(r.visiting[i:]), k)
+			return withSentinel(fmt.Errorf("reference cycle in templates: %s", strings.Join(cycle, " -> ")), ErrParse)
+		}
+	}
+
+	tmpl := r.templates[k]
+	refs := make(map[string]bool)
+	for _, t := range tmpl.Templates() {
+		templateRefs(t.Root, refs)
+	}
+	r.visiting = append(r.visiting, k)
+	for _, ref := range sortedKeys(refs) {
+		if _, ok := r.templates[ref]; !ok || ref == k {
+			continue
+		}
+		if err := r.render(ref); err != nil {
+			return err
+		}
+	}
+	r.visiting = r.visiting[:len(r.visiting)-1]
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, r.data); err != nil {
+		return withSentinel(fmt.Errorf("rendering %s: %w", k, err), ErrValidation)
+	}
+	r.vars[k] = b.String()
+	r.data[k] = b.String()
+	r.rendered[k] = true
+	return nil
+}
+
+// templateRefs adds to refs the keys the template rooted at node refers to
+// with fields such as `{{.PGUSER}}`. Keys looked up otherwise, e.g. with
+// `index`, are not found.
+func templateRefs(node parse.Node, refs map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			templateRefs(c, refs)
+		}
+	case *parse.ActionNode:
+		templateRefs(n.Pipe, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			templateRefs(c, refs)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			templateRefs(a, refs)
+		}
+	case *parse.ChainNode:
+		templateRefs(n.Node, refs)
+	case *parse.FieldNode:
+		refs[n.Ident[0]] = true
+	case *parse.IfNode:
+		templateRefs(n.Pipe, refs)
+		templateRefs(n.List, refs)
+		templateRefs(n.ElseList, refs)
+	case *parse.RangeNode:
+		templateRefs(n.Pipe, refs)
+		templateRefs(n.List, refs)
+		templateRefs(n.ElseList, refs)
+	case *parse.WithNode:
+		templateRefs(n.Pipe, refs)
+		templateRefs(n.List, refs)
+		templateRefs(n.ElseList, refs)
+	case *parse.TemplateNode:
+		templateRefs(n.Pipe, refs)
+	}
+}
@@ -0,0 +1,83 @@
+package udotenv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Transform changes the value of a variable between the parsing of the files
+// and the setting of the environment, e.g. to decode a base64 certificate.
+// Transforms are listed in `Config.Transforms`.
+type Transform func(key, value string) (string, error)
+
+// TrimSpace removes the leading and trailing whitespace of the values.
+func TrimSpace(_, value string) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+// Unquote removes a pair of double quotes, single quotes or backquotes left
+// around the values, e.g. by a tool writing `KEY='"value"'`. Double-quoted
+// values are unescaped like Go strings.
+func Unquote(_, value string) (string, error) {
+	if len(value) < 2 || value[0] != value[len(value)-1] || !strings.ContainsRune(`"'`+"`", rune(value[0])) {
+		return value, nil
+	}
+	if value[0] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+	return strconv.Unquote(value)
+}
+
+// Base64Decode decodes the values from standard base64, with or without
+// padding.
+func Base64Decode(_, value string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		b, err = base64.RawStdEncoding.DecodeString(value)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid base64: %w", err)
+	}
+	return string(b), nil
+}
+
+// ForKeys restricts t to the keys matching one of patterns, in the syntax of
+// path.Match. The values of the other keys are left untouched.
+func ForKeys(t Transform, patterns ...string) Transform {
+	return func(key, value string) (string, error) {
+		if !matchAny(patterns, key) {
+			return value, nil
+		}
+		return t(key, value)
+	}
+}
+
+// Base64For decodes the values of the keys matching patterns from base64,
+// the common way to pass certificates and keys through the environment:
+//
+//	config.Transforms = []udotenv.Transform{udotenv.Base64For("TLS_CERT", "TLS_KEY")}
+func Base64For(patterns ...string) Transform {
+	return ForKeys(Base64Decode, patterns...)
+}
+
+// transform applies the `Transforms` of the config to the values of vars, in
+// order.
+func (ue *UdotEnv) transform(vars map[string]string) error {
+	if ue.Config == nil || len(ue.Config.Transforms) == 0 {
+		return nil
+	}
+
+	for _, k := range sortedKeys(vars) {
+		v := vars[k]
+		for _, t := range ue.Config.Transforms {
+			var err error
+			if v, err = t(k, v); err != nil {
+				return withSentinel(fmt.Errorf("transforming %s: %w", k, ue.maskError(k, vars[k], err)), ErrValidation)
+			}
+		}
+		vars[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package udotenv
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransforms(t *testing.T) {
+	for _, tc := range []struct {
+		transform Transform
+		key, in   string
+		out       string
+	}{
+		{TrimSpace, "K", "  v \t", "v"},
+		{Unquote, "K", `"a\"b"`, `a"b`},
+		{Unquote, "K", `'a\b'`, `a\b`},
+		{Unquote, "K", "`x`", "x"},
+		{Unquote, "K", `"x'`, `"x'`},
+		{Unquote, "K", `"`, `"`},
+		{Base64Decode, "K", "aGVsbG8=", "hello"},
+		{Base64Decode, "K", "aGVsbG8", "hello"},
+		{Base64For("TLS_*"), "TLS_CERT", "aGVsbG8=", "hello"},
+		{Base64For("TLS_*"), "OTHER", "aGVsbG8=", "aGVsbG8="},
+	} {
+		out, err := tc.transform(tc.key, tc.in)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.out, out, tc.in)
+	}
+
+	_, err := Base64Decode("K", "not base64!")
+	assert.Error(t, err)
+}
+
+func TestLoad_Transforms(t *testing.T) {
+	cert := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("TR_CERT="+base64.StdEncoding.EncodeToString([]byte(cert))+"\nTR_NAME=' api '\n"), 0o644)
+	defer os.Unsetenv("TR_CERT")
+	defer os.Unsetenv("TR_NAME")
+
+	config := &Config{Transforms: []Transform{TrimSpace, Base64For("TR_CERT")}}
+	assert.NoError(t, (&UdotEnv{Config: config, EnvParam: stringSlice{path}}).Load())
+	assert.Equal(t, cert, os.Getenv("TR_CERT"))
+	assert.Equal(t, "api", os.Getenv("TR_NAME"))
+
+	vars, err := (&UdotEnv{Config: config}).Read(path)
+	assert.NoError(t, err)
+	assert.Equal(t, cert, vars["TR_CERT"])
+}
+
+func TestLoad_TransformError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("TR_ERR_A=1\nTR_ERR_B=2\n"), 0o644)
+
+	failing := func(key, value string) (string, error) {
+		return "", errors.New("bad value " + value)
+	}
+	config := GetDefaultConfig()
+	config.MaskedKeys = []string{"TR_ERR_B"}
+	config.Transforms = []Transform{ForKeys(failing, "TR_ERR_B")}
+	err := (&UdotEnv{Config: config, EnvParam: stringSlice{path}}).Load()
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.EqualError(t, err, "transforming TR_ERR_B: bad value [masked, 1 bytes]")
+	_, ok := os.LookupEnv("TR_ERR_A")
+	assert.False(t, ok)
+}
@@ -1,18 +1,44 @@
 package udotenv
 
 import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/joho/godotenv"
+	"sync"
+	"time"
 )
 
-const defaultEnvPath = ".env"
+const (
+	defaultEnvPath      = ".env"
+	defaultDefaultsFile = ".env.defaults"
+	defaultPathEnv      = "UDOTENV_PATH"
+	defaultOverloadEnv  = "UDOTENV_OVERLOAD"
+
+	defaultListSeparator     = ","
+	defaultKeyValueSeparator = ":"
+
+	defaultEnvFlagUsage      = "load the env file at `path`, the default one if empty; may be repeated"
+	defaultOverloadFlagUsage = "overwrite the variables already set in the environment"
+	dryRunFlagUsage          = "report the env variables that would be set without setting them"
+)
 const (
 	envsId = iota + 1
 	overloadId
+	dryRunId
 )
 
 type stringSlice []string
@@ -34,17 +60,289 @@ func (s *stringSlice) Set(value string) error {
 //   - EnvFlags: A list of environment variable flags to be used.
 //   - OverloadFlags: A list of flags that determine whether environment variables
 //     should be overloaded.
+//   - DryRunFlags: A list of flags that make Load report the variables it
+//     would set instead of setting them. See Plan.
+//   - EnvFlagUsage: The usage message of the env flags, in the format of the
+//     flag package, where a back-quoted word names the value. A default
+//     message is used if empty.
+//   - OverloadFlagUsage: The usage message of the overload flags. A default
+//     message is used if empty.
 //   - DefaultEnvPath: The default file path to the environment file.
+//   - DefaultEnvPathMode: What a relative `DefaultEnvPath` is relative to: the
+//     working directory by default, the directory of the executable with
+//     RelativeToExecutable, or the one of the source file creating the
+//     instance with RelativeToCaller.
 //   - OverloadByDefault: A boolean indicating whether environment variables should
 //     be overloaded by default.
+//   - MaxKeys: The maximum number of keys that may be applied by a single
+//     load. Zero means no limit.
+//   - MaxEnvBytes: The maximum total size, in bytes, of the applied
+//     "KEY=value" entries. Zero means no limit. With `ContinueOnError`, the
+//     variables exceeding `MaxKeys` or `MaxEnvBytes` are set all the same,
+//     and the breach is reported to `OnWarning`.
+//   - KeyPattern: When set, only the keys matching the pattern are applied.
+//     Other keys are still parsed, so a malformed file is reported either way.
+//     It applies to the keys renamed by `StripPrefix` and `AddPrefix`. Each
+//     key it drops is reported to `OnWarning`.
+//   - Prefix: When set, only the keys starting with the prefix, e.g. "MYAPP_",
+//     are applied, so that several components can share a file.
+//   - StripPrefix: A boolean indicating whether the `Prefix` is removed from
+//     the keys, so that MYAPP_PORT is applied as PORT.
+//   - AddPrefix: A prefix added to every key applied, to namespace the
+//     variables of the files.
+//   - Aliases: Legacy key names mapped to the canonical ones, e.g. "DB_URL" to
+//     "DATABASE_URL", so that the files still using the legacy names set the
+//     canonical variables. A canonical key defined in the files wins over its
+//     aliases, and each use of an alias is reported to `OnWarning`.
+//   - ProtectedKeys: Keys, e.g. "PATH" and "HOME", that are never overwritten
+//     once in the environment, even with overload. The attempts are reported
+//     to `OnWarning`.
+//   - OverloadOnly: When set, only the keys matching one of these glob
+//     patterns, e.g. "LOG_LEVEL" or "FEATURE_*", overwrite the variables
+//     already in the environment, whether or not overload is requested. The
+//     other keys are only loaded if they are not set yet.
+//   - DefaultsFile: The path to a file that is always loaded, regardless of the
+//     flags passed, with the lowest precedence: its variables never override
+//     the environment or the variables from the other files. The file is
+//     skipped if it does not exist. The default config uses ".env.defaults",
+//     so that a repository can commit safe defaults while the real env files
+//     stay ignored.
+//   - Decompress: A boolean indicating whether files starting with the gzip
+//     magic header should be decompressed. Files with a `.gz` extension are
+//     always decompressed.
+//   - NormalizeBools: A list of keys whose values are rewritten to "true" or
+//     "false". Values such as "1", "yes" or "On" are accepted; a value that is
+//     not a boolean makes the load fail.
+//   - RejectPaddedValues: A boolean indicating whether an unquoted value with
+//     leading or trailing whitespace makes the load fail. Such whitespace is
+//     usually a typo; quote the value if it is intended.
+//   - ProfileVar: The name of the variable holding the profile, e.g. APP_ENV.
+//     When set, the files of `ProfileOrder` are loaded before the ones passed
+//     through the flags, which take precedence over them.
+//   - ProfileOrder: The profile files, from the lowest to the highest
+//     precedence. "{profile}" is replaced by the value of `ProfileVar`; files
+//     containing it are skipped when the profile is empty, and missing files
+//     are skipped as well. It defaults to `.env`, `.env.{profile}`,
+//     `.env.local`, `.env.{profile}.local`.
+//   - Precedence: Which file wins when several files passed through the flags
+//     define the same key. See Precedence.
+//   - OverloadFiles: The paths of the files whose variables overwrite the
+//     environment even without the overload flag.
+//   - SecretResolver: A function resolving secret references. Values starting
+//     with `SecretScheme` are passed to it and replaced by the returned
+//     plaintext before they are applied.
+//   - SecretScheme: The prefix marking a value as a secret reference. It
+//     defaults to "secret://".
+//   - SOPSDecrypt: A function decrypting the env files encrypted with SOPS,
+//     which are detected by their metadata. It defaults to running
+//     `sops --decrypt`, so the sops binary must be in PATH along with the
+//     credentials of the key backend (age, PGP or a cloud KMS).
+//   - AgeIdentityFile: The path to the age identities used to decrypt the env
+//     files with a `.age` extension. When empty, the identities are read from
+//     the UDOTENV_AGE_KEY variable. Decrypted content is never written to
+//     disk.
+//   - DotenvVaultFile: The path to the dotenv-vault file, decrypted instead of
+//     the files passed through the flags when DOTENV_KEY is set. It defaults
+//     to `.env.vault`.
+//   - Expand: A boolean indicating whether `$VAR`, `${VAR}`, `${VAR:-default}`
+//     and `${VAR-default}` references are expanded across all the loaded
+//     files and the environment, instead of only from the keys defined
+//     earlier in the same file. A reference cycle makes the load fail.
+//   - AllowCommandSubstitution: A boolean indicating whether `$(command)` in
+//     unquoted and double-quoted values is replaced by the output of the
+//     command, run with the shell at load time. Only enable it for trusted
+//     files, as they can then run any command.
+//   - CommandTimeout: The maximum duration of a command substitution. It
+//     defaults to 10 seconds.
+//   - MaxIncludeDepth: How deeply `#include path` and `dotenv_include=path`
+//     directives may be nested. It defaults to 8.
+//   - FileRefSuffix: When set, a variable whose key ends with the suffix,
+//     e.g. "_FILE", names a file whose content becomes the value of the key
+//     without the suffix, following the Docker convention for secrets:
+//     DB_PASSWORD_FILE=/run/secrets/db_password sets DB_PASSWORD. The
+//     references of the files are all considered, while the ones already in
+//     the environment only are for the `KnownKeys`, the `RequiredKeys` and
+//     the keys of the `Schema`, so that standard variables such as
+//     SSL_CERT_FILE are left alone. Trailing line breaks of the content are
+//     trimmed.
+//   - Format: The format of the env files. It defaults to FormatAuto, which
+//     detects it from the extension of each file: `.json` files are JSON
+//     objects, `.yaml` or `.yml` files YAML mappings and `.toml` files TOML
+//     documents, whose nested objects are flattened into PARENT_CHILD keys,
+//     and other files use the dotenv syntax.
+//   - KeySeparator: The separator joining the names of nested objects when
+//     flattening JSON, YAML and TOML files. It defaults to "_".
+//   - Dialect: The flavour of the dotenv syntax. It defaults to
+//     DialectGodotenv; with DialectSystemd, the files are parsed like the
+//     EnvironmentFile= files of systemd, and with DialectCompose, the values
+//     are interpolated like Docker Compose does, across files with `Expand`.
+//     Includes, command substitution and `RejectPaddedValues` do not apply to
+//     DialectSystemd.
+//   - DuplicateKeys: What happens when a file defines the same key several
+//     times: the last definition wins by default, like with godotenv, the
+//     first one with DuplicateFirstWins, and the load fails with
+//     DuplicateError. It does not apply to DialectSystemd.
+//   - ContinueOnError: A boolean indicating whether Load still loads the
+//     files that could be read when others fail. The errors are returned all
+//     the same once the variables are set.
+//   - IgnoreMissing: A boolean indicating whether the files passed through
+//     the flags are skipped if they do not exist, instead of failing Load.
+//   - OptionalFiles: The files passed through the flags that are skipped if
+//     they do not exist, e.g. ".env.local", while the others are still
+//     required. It has no effect with `IgnoreMissing`.
+//   - SearchParents: A boolean indicating whether a relative file passed
+//     through the flags that is missing from the working directory is looked
+//     up in the parent directories, up to the root of the git repository or
+//     of the filesystem, so that the nearest `.env` is found from anywhere in
+//     a monorepo.
+//   - UseXDG: A boolean indicating whether, when no file is passed through the
+//     flags, Load reads the first existing file among the `DefaultEnvPath`,
+//     `$XDG_CONFIG_HOME/<app>/env` and `/etc/<app>/env`, as daemons usually
+//     do. XDG_CONFIG_HOME defaults to `~/.config`, macOS included. Nothing
+//     is loaded if none exists.
+//   - AppName: The name of the application in the paths of `UseXDG`. It
+//     defaults to the name of the executable.
+//   - PathSeparators: The characters splitting a value of the env flags into
+//     several files, e.g. ":," to accept `-e base.env:local.env` or a
+//     comma-separated list from a CI variable. Values are not split if empty.
+//     The `:` of URL schemes and ports is not a separator.
+//   - PathEnv: The name of the environment variable listing the files to load
+//     when none is passed through the flags, so that containers can be
+//     configured by their orchestrator. The files are separated by the
+//     `PathSeparators`, or by commas if there are none. It is `UDOTENV_PATH`
+//     in the default configuration; the variable is ignored if empty.
+//   - OverloadEnv: The name of the environment variable that turns overload
+//     on when set to a true value such as `1` or `true`, as the overload
+//     flags do. It is `UDOTENV_OVERLOAD` in the default configuration; the
+//     variable is ignored if empty.
+//   - Strict: A boolean indicating whether Load fails when the files define
+//     keys the application does not know, e.g. a misspelled DATABSE_URL. The
+//     known keys are the `KnownKeys`, the `RequiredKeys` and the keys of the
+//     `Schema`, along with their file references.
+//   - KnownKeys: The keys the application reads, for `Strict`. KeysOf lists
+//     the keys of a struct bound with Bind.
+//   - ErrorHandling: How New and Load report errors: returned, printed before
+//     exiting, or raised as panics. See ErrorHandling.
+//   - PanicOnError: A boolean indicating whether Load should panic instead of
+//     returning an error, which was the behaviour of earlier versions.
+//     Deprecated: set `ErrorHandling` to PanicOnError instead.
+//   - PreserveArgs: A boolean indicating whether New should leave `os.Args`
+//     untouched. The env and overload flags are parsed from a copy of the
+//     arguments and the remaining ones are available through Args.
+//   - HTTP: How env files passed as http:// or https:// URLs are fetched:
+//     timeout, headers and TLS settings. See HTTPConfig.
+//   - Sources: Custom sources of variables, loaded along with the files passed
+//     through the flags, after them. See Source.
+//   - Templates: A boolean indicating whether the values of the env files
+//     containing `{{` are rendered as Go templates, e.g.
+//     `DSN=postgres://{{.PGUSER}}:{{.PGPASS}}@{{.PGHOST}}/app`. The data is
+//     the environment as it will be after the load, with the templates
+//     referred to rendered first. Missing keys render as empty strings. The
+//     templates are rendered before file references and secrets are
+//     resolved, and the values of Sources, registered schemes, file
+//     references, secrets and command substitutions are never rendered.
+//   - TemplateMissingKeyError: A boolean indicating whether a template
+//     referring to a key that is not set fails the load instead.
+//   - Transforms: The transforms applied in order to the values once they are
+//     resolved, before they are checked and set, such as TrimSpace or
+//     Base64For("TLS_CERT"). See Transform.
+//   - OnBeforeSet: A function called with each variable about to be set in
+//     the environment, once the values are resolved and before they are
+//     checked. It returns the value to set, possibly transformed, and false
+//     to veto the variable, which is then left out of the load. Plan and the
+//     dry-run flags, which set nothing, do not call it.
+//   - OnAfterLoad: A function called at the end of each load, including the
+//     reloads, with the variables it set or the error that made it fail, e.g.
+//     to collect metrics.
+//   - OnReload: A function called after each reload triggered by
+//     ReloadOnSignal or Watch, with the changed variables or the error that
+//     made the reload fail.
+//   - OnWarning: A function called with the problems that do not fail the
+//     load, such as the use of a deprecated alias.
+//   - Logger: A logger receiving debug logs of what Load does: the files read
+//     or skipped, the variables set and the ones skipped because they were
+//     already in the environment. The warnings are logged as well. Values
+//     are never logged. Nothing is logged if nil.
+//   - MaskedKeys: Patterns, in the syntax of path.Match and matched
+//     regardless of case, of the keys whose values are masked in the errors
+//     and diagnostics, showing only their length. They are `*TOKEN*`,
+//     `*SECRET*`, `*PASSWORD*` and `*KEY*` in the default configuration.
+//   - ListSeparator: The separator of the elements of the slices and the
+//     entries of the maps decoded by Get and Bind, "," if empty.
+//   - KeyValueSeparator: The separator of the keys and values of the map
+//     entries decoded by Get and Bind, ":" if empty.
 type Config struct {
-	EnvFlags          []string
-	OverloadFlags     []string
-	DefaultEnvPath    string
-	OverloadByDefault bool
+	EnvFlags                 []string
+	OverloadFlags            []string
+	DryRunFlags              []string
+	EnvFlagUsage             string
+	OverloadFlagUsage        string
+	DefaultEnvPath           string
+	DefaultEnvPathMode       PathMode
+	OverloadByDefault        bool
+	MaxKeys                  int
+	MaxEnvBytes              int
+	KeyPattern               *regexp.Regexp
+	Prefix                   string
+	StripPrefix              bool
+	AddPrefix                string
+	Aliases                  map[string]string
+	ProtectedKeys            []string
+	OverloadOnly             []string
+	DefaultsFile             string
+	Decompress               bool
+	NormalizeBools           []string
+	RejectPaddedValues       bool
+	ProfileVar               string
+	ProfileOrder             []string
+	Precedence               Precedence
+	OverloadFiles            []string
+	SecretResolver           func(ctx context.Context, ref string) (string, error)
+	SecretScheme             string
+	SOPSDecrypt              func(ctx context.Context, src []byte) ([]byte, error)
+	AgeIdentityFile          string
+	DotenvVaultFile          string
+	Expand                   bool
+	AllowCommandSubstitution bool
+	CommandTimeout           time.Duration
+	MaxIncludeDepth          int
+	FileRefSuffix            string
+	Format                   Format
+	KeySeparator             string
+	Dialect                  Dialect
+	DuplicateKeys            DuplicatePolicy
+	ContinueOnError          bool
+	IgnoreMissing            bool
+	OptionalFiles            []string
+	SearchParents            bool
+	UseXDG                   bool
+	AppName                  string
+	PathSeparators           string
+	PathEnv                  string
+	OverloadEnv              string
+	Strict                   bool
+	KnownKeys                []string
+	ErrorHandling            ErrorHandling
+	PanicOnError             bool
+	PreserveArgs             bool
+	RequiredKeys             []string
+	Schema                   Schema
+	HTTP                     *HTTPConfig
+	Sources                  []Source
+	Templates                bool
+	TemplateMissingKeyError  bool
+	Transforms               []Transform
+	OnBeforeSet              func(key, value string) (string, bool)
+	OnAfterLoad              func(loaded []LoadedKey, err error)
+	OnReload                 func(changed map[string]Change, err error)
+	OnWarning                func(msg string)
+	Logger                   *slog.Logger
+	MaskedKeys               []string
+	ListSeparator            string
+	KeyValueSeparator        string
 }
 
-// udotEnvType represents the environment configuration structure for the application.
+// UdotEnv represents the environment configuration structure for the application.
 // It contains the configuration settings, an environment parameter, and a flag
 // to determine whether to overload existing parameters.
 //
@@ -52,143 +350,925 @@ type Config struct {
 // - Config: A pointer to the Config structure that holds the application's configuration settings.
 // - EnvParam: A string representing the environment parameter to be used.
 // - OverloadParam: A boolean flag indicating whether to overwrite existing environment parameters.
-type udotEnvType struct {
+// - DryRunParam: A boolean flag indicating whether Load only reports the variables it would set.
+//
+// Once its flags are parsed, an UdotEnv is safe for concurrent use: a goroutine
+// may reload the files with Load, Reload or Watch while others read the loaded
+// values with the getters, Bind or LoadedKeys. The fields must not be modified
+// concurrently, and the callbacks of the config must not call back into the
+// instance while it loads.
+type UdotEnv struct {
 	Config        *Config
 	EnvParam      stringSlice
 	OverloadParam bool
+	DryRunParam   bool
+
+	mu     sync.RWMutex // guards the fields below
+	stamps map[string]fileStamp
+	// stamps of the files included by the files being loaded, nil outside
+	// of Load
+	includeStamps map[string]fileStamp
+	vars          map[string]string
+	owned         map[string]bool // variables set by udotenv
+	// variables from the files of the last Load
+	fromFiles map[string]bool
+	loaded    []LoadedKey // variables set by the last load
+	args      []string
+	err       error           // error of New, returned by Load with ReturnOnError
+	ctx       context.Context // context of the load in progress, see LoadContext
+	flagSet   *flag.FlagSet
+
+	httpMu     sync.Mutex   // guards the fields below
+	httpClient *http.Client // default client for remote files, see fetch
+	httpTLS    *tls.Config  // TLS config httpClient was built with
+}
+
+// Loader is the interface implemented by UdotEnv. Code that only needs to
+// trigger the load, e.g. application bootstrap or its tests, can depend on it
+// instead of the concrete type.
+type Loader interface {
+	// Load loads the env files into the environment.
+	Load() error
+	// Files returns the paths of the env files to load.
+	Files() []string
+	// Overload reports whether existing variables are overloaded.
+	Overload() bool
+}
+
+var _ Loader = (*UdotEnv)(nil)
+
+// Files returns a copy of the paths of the env files passed through the env
+// flags, in the order they were passed, split on the `PathSeparators` of the
+// config. Without env flags, they are taken from its `PathEnv` variable.
+func (ue *UdotEnv) Files() []string {
+	return append([]string{}, ue.envParams()...)
+}
+
+// Overload reports whether Load overwrites variables that already exist in
+// the environment, as set by the overload flags or the `OverloadEnv`
+// variable of the config.
+func (ue *UdotEnv) Overload() bool {
+	if ue.OverloadParam {
+		return true
+	}
+	if ue.Config == nil || ue.Config.OverloadEnv == "" {
+		return false
+	}
+	overload, _ := strconv.ParseBool(os.Getenv(ue.Config.OverloadEnv))
+	return overload
 }
 
 // Load reads environment variables from a specified file and loads them into
 // the application's environment. If the `OverloadParam` field is set to true,
 // it will overwrite existing environment variables with the values from the file.
 //
-// The method uses the `godotenv` package to handle the loading process. If the
-// `EnvParam` field is empty, the method returns immediately without performing
-// any action. If an error occurs while loading the file, the method will panic
-// with an error message.
+// Besides the `EnvParam` files, the `DefaultsFile` and the profile files of the
+// config are loaded, with a lower precedence. An error is
+// returned if a file cannot be read, a value is rejected by the config (see
+// `NormalizeBools` and `RejectPaddedValues`), a secret or a file reference
+// (see `FileRefSuffix`) cannot be resolved, the variables exceed the limits
+// set by `MaxKeys` or `MaxEnvBytes`, some of the `RequiredKeys` are missing,
+// or the values do not satisfy the `Schema`. Errors about a file wrap the
+// underlying error and include the path of the file; syntax errors are
+// *ParseError values giving the position of the problem. When several files
+// fail, all their errors are reported, joined with errors.Join. Errors match
+// ErrFileNotFound, ErrParse or ErrValidation with errors.Is, depending on
+// their cause.
+// Everything is checked before any variable is set, so a failed load leaves
+// the environment untouched, unless `ContinueOnError` is set.
+//
+// Load may be called again to pick up changes to the files. Variables set by a
+// previous call are then updated, or removed if they are no longer in the
+// files, while variables that were in the environment beforehand are still
+// only overwritten with overload. Loading unchanged files again sets the same
+// values and reports no change, so Load is idempotent. Concurrent calls are
+// serialized.
+//
+// The error is reported according to the `ErrorHandling` of the config: with
+// PanicOnError, or the deprecated `PanicOnError` field, the method panics with
+// the error message, and with ExitOnError it exits the program. With
+// ReturnOnError, the error New ran into is returned by each call.
+//
+// With `DryRunParam`, set by the dry-run flags, Load only prints the plan of
+// the load to stderr, one variable per line. See Plan.
 //
 // Note: Ensure that `EnvParam` is set to the path of the environment file before
 // calling this method.
 //
 // Example:
 //
-//	ue := &udotEnv{
+//	ue := &UdotEnv{
 //	    EnvParam:      []string{".env"},
 //	    OverloadParam: false,
 //	}
-//	ue.Load() // Loads environment variables from the .env file.
-func (ue *udotEnvType) Load() {
-	if len(ue.EnvParam) == 0 {
-		return
+//	err := ue.Load() // Loads environment variables from the .env file.
+func (ue *UdotEnv) Load() error {
+	return ue.LoadContext(context.Background())
+}
+
+// LoadContext is like Load, but stops as soon as ctx is done: the remote
+// files, the custom sources, the secret resolvers, the command
+// substitutions and the reading of the files get ctx, and the load fails
+// with its error, leaving the environment untouched.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	err := ue.LoadContext(ctx)
+func (ue *UdotEnv) LoadContext(ctx context.Context) error {
+	if ue.err != nil {
+		return ue.fail(ue.err)
+	}
+
+	ue.mu.Lock()
+	ue.ctx = ctx
+	var err error
+	if ue.DryRunParam {
+		var plan []LoadedKey
+		plan, err = ue.plan()
+		for _, k := range plan {
+			fmt.Fprintln(os.Stderr, "udotenv: would set", k)
+		}
+	} else {
+		_, err = ue.load()
+	}
+	ue.ctx = nil
+	ue.mu.Unlock()
+	return ue.fail(err)
+}
+
+// loadContext returns the context of the load in progress, or the
+// background context outside of LoadContext.
+func (ue *UdotEnv) loadContext() context.Context {
+	if ue.ctx != nil {
+		return ue.ctx
+	}
+	return context.Background()
+}
+
+// fail reports err, an error of Load, according to the `ErrorHandling` of the
+// config: it exits with ExitOnError, panics with the error message with
+// PanicOnError and returns err otherwise.
+func (ue *UdotEnv) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch ue.errorHandling() {
+	case ExitOnError:
+		fmt.Fprintln(os.Stderr, "udotenv:", err)
+		exit(2)
+	case PanicOnError:
+		panic(err.Error())
 	}
+	return err
+}
+
+// load loads the files and returns the changes it made to the values of the
+// previously loaded variables.
+func (ue *UdotEnv) load() (map[string]Change, error) {
+	changes, err := ue.loadFiles()
+	ue.afterLoad(err)
+	return changes, err
+}
 
-	f := godotenv.Load
-	if ue.OverloadParam == true {
-		f = godotenv.Overload
+// loadFiles implements load.
+func (ue *UdotEnv) loadFiles() (map[string]Change, error) {
+	if err := ue.loadContext().Err(); err != nil {
+		return nil, err
 	}
 
-	err := f(ue.EnvParam...)
+	// the files are stamped before they are read, so that a file rewritten in
+	// between is seen as changed by the next call to Changed
+	stamps, err := stampFiles(ue.filePaths())
 	if err != nil {
-		panic(fmt.Sprintln("error loading file '", ue.EnvParam, "'"))
+		return nil, err
+	}
+
+	ue.includeStamps = stamps
+	layers, readErr := ue.fileLayers()
+	ue.includeStamps = nil
+	if readErr != nil && !ue.continueOnError() {
+		return nil, readErr
+	}
+
+	merged, err := ue.resolve(layers)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := ue.apply(merged, true)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := maps.Clone(ue.vars)
+	if vars == nil {
+		vars = make(map[string]string, len(loaded))
+	}
+	// variables set by a previous load and no longer in the files are removed
+	for k := range ue.fromFiles {
+		if _, ok := loaded[k]; ok {
+			continue
+		}
+		if ue.owned[k] {
+			os.Unsetenv(k)
+			delete(ue.owned, k)
+		}
+		delete(vars, k)
+	}
+	maps.Copy(vars, loaded)
+
+	changes := Diff(ue.vars, vars)
+	ue.vars = vars
+	ue.fromFiles = make(map[string]bool, len(loaded))
+	for k := range loaded {
+		ue.fromFiles[k] = true
+	}
+	ue.stamps = stamps
+	return changes, readErr
+}
+
+// continueOnError reports whether `ContinueOnError` is set in the config.
+func (ue *UdotEnv) continueOnError() bool {
+	return ue.Config != nil && ue.Config.ContinueOnError
+}
+
+// apply checks the merged variables and sets the pending ones in the
+// environment. The `RequiredKeys` are only checked if required is true. It
+// returns the resulting value of every merged variable and of the targets of
+// the file references.
+func (ue *UdotEnv) apply(merged map[string]entry, required bool) (map[string]string, error) {
+	pending, err := ue.prepare(merged, required, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if ue.owned == nil {
+		ue.owned = make(map[string]bool, len(pending))
+	}
+	loaded := ue.planned(merged, pending)
+	for _, k := range sortedKeys(pending) {
+		if err := os.Setenv(k, pending[k]); err != nil {
+			return nil, fmt.Errorf("setting %s: %w", k, err)
+		}
+		ue.owned[k] = true
+		ue.logger().Debug("variable set", "key", k, "source", ue.source(merged, k))
+	}
+	ue.loaded = loaded
+
+	vars := make(map[string]string, len(merged))
+	for k := range merged {
+		vars[k] = os.Getenv(k)
+	}
+	// the targets of the file references are not part of merged
+	for k, v := range pending {
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// prepare returns the merged variables to set in the environment, with the
+// file references and the secrets resolved, once they pass the checks of the
+// config. The `OnBeforeSet` hook is only called if set is true, i.e. when the
+// variables are about to be set rather than planned.
+func (ue *UdotEnv) prepare(merged map[string]entry, required, set bool) (map[string]string, error) {
+	if err := ue.checkStrict(merged); err != nil {
+		return nil, err
+	}
+
+	pending := ue.pending(merged)
+
+	// before the file references and the secrets are resolved, so that
+	// their content is never run as a template
+	if err := ue.renderTemplates(merged, pending); err != nil {
+		return nil, err
+	}
+
+	if err := ue.resolveFileRefs(merged, pending); err != nil {
+		return nil, err
+	}
+
+	if err := ue.resolveSecrets(ue.loadContext(), pending); err != nil {
+		return nil, err
+	}
+
+	if err := ue.transform(pending); err != nil {
+		return nil, err
+	}
+
+	if err := ue.normalizeBools(pending); err != nil {
+		return nil, err
+	}
+
+	if set {
+		ue.beforeSet(merged, pending)
+	}
+
+	if err := ue.checkLimits(pending); err != nil {
+		if !ue.continueOnError() {
+			return nil, err
+		}
+		ue.warn("%v", err)
+	}
+
+	if required {
+		if err := ue.checkRequired(pending); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ue.checkSchema(pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// planned describes the pending variables, sorted by key, before they are set
+// in the environment.
+func (ue *UdotEnv) planned(merged map[string]entry, pending map[string]string) []LoadedKey {
+	keys := make([]LoadedKey, 0, len(pending))
+	for _, k := range sortedKeys(pending) {
+		_, set := os.LookupEnv(k)
+		keys = append(keys, LoadedKey{Key: k, Source: ue.source(merged, k), Overridden: set && !ue.owned[k]})
+	}
+	return keys
+}
+
+// source returns the path of the layer defining the variable k of merged. The
+// targets of the file references come from the layer of the reference.
+func (ue *UdotEnv) source(merged map[string]entry, k string) string {
+	if e, ok := merged[k]; ok {
+		return e.source
+	}
+	if ue.Config != nil {
+		return merged[k+ue.Config.FileRefSuffix].source
+	}
+	return ""
+}
+
+// filter renames the variables defined by the `Aliases` of the config, keeps
+// the ones whose keys start with the `Prefix`, renames them according to
+// `StripPrefix` and `AddPrefix`, then drops the ones whose keys do not match
+// the `KeyPattern`. Only the `OverloadOnly` keys overload the environment if
+// any are given, and never the `ProtectedKeys`.
+func (ue *UdotEnv) filter(vars map[string]entry) map[string]entry {
+	if ue.Config == nil {
+		return vars
+	}
+	if len(ue.Config.Aliases) > 0 {
+		vars = ue.alias(vars)
+	}
+	if ue.Config.Prefix != "" || ue.Config.AddPrefix != "" {
+		vars = ue.prefix(vars)
+	}
+	if len(ue.Config.OverloadOnly) > 0 {
+		for k, e := range vars {
+			e.overload = matchAny(ue.Config.OverloadOnly, k)
+			vars[k] = e
+		}
+	}
+	if len(ue.Config.ProtectedKeys) > 0 {
+		ue.protect(vars)
+	}
+	if ue.Config.KeyPattern == nil {
+		return vars
+	}
+
+	for _, k := range sortedKeys(vars) {
+		if !ue.Config.KeyPattern.MatchString(k) {
+			ue.warn("%s from %s does not match KeyPattern and is not loaded", k, vars[k].source)
+			delete(vars, k)
+		}
+	}
+	return vars
+}
+
+// alias renames the variables of vars whose keys are `Aliases` of the config
+// to their canonical keys, unless vars defines those as well.
+func (ue *UdotEnv) alias(vars map[string]entry) map[string]entry {
+	for _, k := range sortedKeys(vars) {
+		canonical, ok := ue.Config.Aliases[k]
+		if !ok {
+			continue
+		}
+
+		e := vars[k]
+		delete(vars, k)
+		_, defined := vars[canonical]
+		msg := fmt.Sprintf("%s is deprecated, use %s", k, canonical)
+		if defined {
+			msg = fmt.Sprintf("%s is deprecated and ignored, since %s is defined", k, canonical)
+		}
+		if e.source != "" {
+			msg = e.source + ": " + msg
+		}
+		ue.warn("%s", msg)
+		if !defined {
+			vars[canonical] = e
+		}
+	}
+	return vars
+}
+
+// protect keeps the `ProtectedKeys` of vars that are already in the
+// environment from overloading it.
+func (ue *UdotEnv) protect(vars map[string]entry) {
+	for _, k := range sortedKeys(vars) {
+		if e := vars[k]; e.overload && ue.protected(k) {
+			e.overload = false
+			vars[k] = e
+		}
+	}
+}
+
+// overloadsKey reports whether the merged variable k, whose layer overloads
+// the environment if overload is true, still does once filter applies the
+// `OverloadOnly` and `ProtectedKeys` of the config. Unlike protect, it
+// reports nothing.
+func (ue *UdotEnv) overloadsKey(k string, overload bool) bool {
+	if ue.Config == nil {
+		return overload
+	}
+	if len(ue.Config.OverloadOnly) > 0 {
+		overload = matchAny(ue.Config.OverloadOnly, k)
+	}
+	if overload && slices.Contains(ue.Config.ProtectedKeys, k) {
+		_, inEnv := os.LookupEnv(k)
+		return !inEnv
+	}
+	return overload
+}
+
+// protected reports whether k is one of the `ProtectedKeys` of the config and
+// is in the environment, in which case the attempt to overwrite it is
+// reported.
+func (ue *UdotEnv) protected(k string) bool {
+	if ue.Config == nil || !slices.Contains(ue.Config.ProtectedKeys, k) {
+		return false
 	}
+	if _, ok := os.LookupEnv(k); !ok {
+		return false
+	}
+	ue.warn("%s is protected and not overwritten", k)
+	return true
+}
+
+// matchAny reports whether k matches one of the glob patterns. Malformed
+// patterns match nothing.
+func matchAny(patterns []string, k string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		ok, _ := path.Match(pattern, k)
+		return ok
+	})
+}
+
+// warn reports a problem that does not fail the load to the `OnWarning`
+// function of the config.
+func (ue *UdotEnv) warn(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	ue.logger().Warn(msg)
+	if ue.Config != nil && ue.Config.OnWarning != nil {
+		ue.Config.OnWarning(msg)
+	}
+}
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// logger returns the `Logger` of the config, or a logger that discards
+// everything.
+func (ue *UdotEnv) logger() *slog.Logger {
+	if ue.Config != nil && ue.Config.Logger != nil {
+		return ue.Config.Logger
+	}
+	return discardLogger
+}
+
+// prefix applies the `Prefix`, `StripPrefix` and `AddPrefix` of the config to
+// vars.
+func (ue *UdotEnv) prefix(vars map[string]entry) map[string]entry {
+	renamed := make(map[string]entry, len(vars))
+	for k, e := range vars {
+		rest, ok := strings.CutPrefix(k, ue.Config.Prefix)
+		if !ok {
+			continue
+		}
+		if ue.Config.StripPrefix {
+			if rest == "" {
+				continue
+			}
+			k = rest
+		}
+		renamed[ue.Config.AddPrefix+k] = e
+	}
+	return renamed
+}
+
+// pending returns the variables that would be applied, i.e. the ones not yet
+// present in the environment, the ones set by a previous load, and the ones
+// allowed to overload the environment.
+func (ue *UdotEnv) pending(vars map[string]entry) map[string]string {
+	pending := make(map[string]string, len(vars))
+	for k, e := range vars {
+		if _, ok := os.LookupEnv(k); ok && !e.overload && !ue.owned[k] {
+			ue.logger().Debug("variable already set, skipped", "key", k, "source", e.source)
+			continue
+		}
+		pending[k] = e.value
+	}
+	return pending
+}
+
+// checkLimits verifies that vars fit into the `MaxKeys` and `MaxEnvBytes`
+// limits of the config.
+func (ue *UdotEnv) checkLimits(vars map[string]string) error {
+	if ue.Config == nil {
+		return nil
+	}
+
+	if max := ue.Config.MaxKeys; max > 0 && len(vars) > max {
+		return withSentinel(fmt.Errorf("too many keys to load: %d, limit is %d", len(vars), max), ErrValidation)
+	}
+
+	if max := ue.Config.MaxEnvBytes; max > 0 {
+		size := 0
+		for k, v := range vars {
+			size += len(k) + len(v) + 1
+		}
+		if size > max {
+			return withSentinel(fmt.Errorf("environment too large to load: %d bytes in %d keys, limit is %d bytes",
+				size, len(vars), max), ErrValidation)
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // GetDefaultConfig returns a pointer to a Config struct initialized with
 // default values. The default configuration includes predefined flags for
 // environment variables and overload options, as well as a default path
-// for the environment file.
+// for the environment file, the `.env.defaults` defaults file and the
+// `UDOTENV_PATH` and `UDOTENV_OVERLOAD` variables.
 func GetDefaultConfig() *Config {
 	return &Config{
 		EnvFlags:       []string{"envs", "e"},
 		OverloadFlags:  []string{"env-overload", "eo", "o"},
+		DryRunFlags:    []string{"env-dry-run"},
 		DefaultEnvPath: defaultEnvPath,
+		DefaultsFile:   defaultDefaultsFile,
+		MaskedKeys:     slices.Clone(defaultMaskedKeys),
+		PathEnv:        defaultPathEnv,
+		OverloadEnv:    defaultOverloadEnv,
 	}
 }
 
-// New creates and initializes a new instance of udotEnvType with the provided configuration.
+// New creates and initializes a new instance of UdotEnv with the provided configuration.
 //
 // Parameters:
 //   - parseFlags: A boolean indicating whether to parse command-line flags immediately.
+//     Deprecated: pass false and call Parse or MustParse, which make the parsing an
+//     explicit step that reports its errors.
 //   - config: Optional variadic parameter to pass a single *Config instance. If no configuration
 //     is provided, a default configuration will be used. If more than one configuration is passed,
 //     the function will panic.
+//     Deprecated: use NewWithOptions with WithConfig, the option-based form of New, which
+//     keeps this signature for compatibility.
 //
 // Behavior:
 //   - If no configuration is provided, the default configuration is used.
-//   - If a configuration is provided, it is used to initialize the udotEnvType instance. If the
+//   - If a configuration is provided, it is used to initialize the UdotEnv instance. If the
 //     DefaultEnvPath in the configuration is empty, it is set to a predefined default value.
-//   - Command-line flags are registered based on the EnvFlags and OverloadFlags in the configuration.
+//   - Command-line flags are registered on the global flag set based on the EnvFlags and
+//     OverloadFlags in the configuration, and `os.Args` is rewritten with PrepareArgs.
 //     Flags are stored in a map to ensure that only one flag per parameter is passed.
 //   - If the `parseFlags` parameter is true, the function will parse the command-line flags.
+//   - If `PreserveArgs` is set in the configuration, `os.Args` is left untouched. The env and
+//     overload flags are parsed right away and, if `parseFlags` is true, the remaining
+//     arguments returned by Args are parsed by the global flag set.
 //
 // Panics:
 //   - If more than one configuration is passed.
+//   - With a *ConfigError if the flags of the configuration are invalid or already
+//     defined on the global flag set. See Config.Validate.
 //   - If multiple flags for the same parameter are passed.
 //
+// The last two errors are reported according to the `ErrorHandling` of the
+// configuration instead, if it is set: ReturnOnError keeps them for Load to
+// return and ExitOnError prints them and exits.
+//
 // Returns:
-//   - A pointer to the initialized udotEnvType instance.
-func New(parseFlags bool, config ...*Config) (udotEnv *udotEnvType) {
-	udotEnv = &udotEnvType{}
-	if len(config) == 0 {
-		udotEnv.Config = GetDefaultConfig()
+//   - A pointer to the initialized UdotEnv instance.
+func New(parseFlags bool, config ...*Config) (udotEnv *UdotEnv) {
+	var cfg *Config
+	if len(config) == 1 {
+		cfg = config[0]
+	} else if len(config) > 1 {
+		panic("only 1 config must be passed")
+	}
 
-	} else if len(config) == 1 {
-		udotEnv.Config = config[0]
-		if udotEnv.Config.DefaultEnvPath == "" {
-			udotEnv.Config.DefaultEnvPath = defaultEnvPath
+	udotEnv = NewWithFlagSet(flag.CommandLine, cfg)
+	if len(os.Args) <= 1 {
+		return
+	}
+
+	if udotEnv.err != nil {
+		return
+	}
+
+	if udotEnv.Config.PreserveArgs {
+		if err := udotEnv.extractArgs(os.Args[0], os.Args[1:]); err != nil {
+			udotEnv.failNew(err)
+			return
 		}
+		if parseFlags {
+			flag.CommandLine.Parse(udotEnv.args)
+		}
+		return
+	}
+	args, err := udotEnv.prepareArgs(os.Args[1:])
+	if err != nil {
+		udotEnv.failNew(err)
+		return
+	}
+	os.Args = append(os.Args[:1:1], args...)
 
-	} else {
-		panic("only 1 config must be passed")
+	if parseFlags {
+		flag.Parse()
+	}
+	return
+}
+
+// extractArgs parses the env and overload flags found in args and keeps the
+// other arguments, in order, for Args.
+func (ue *UdotEnv) extractArgs(name string, args []string) error {
+	ids := ue.flagIds()
+	own := flag.NewFlagSet(name, flag.ContinueOnError)
+	own.SetOutput(io.Discard)
+	ue.register(own)
+
+	var ownArgs []string
+	ue.args = []string{}
+	prepared, err := ue.prepareArgs(args)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(prepared); i++ {
+		arg := prepared[i]
+		flagName, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		id, ok := ids[flagName]
+		if !strings.HasPrefix(arg, "-") || !ok {
+			ue.args = append(ue.args, arg)
+			continue
+		}
+
+		ownArgs = append(ownArgs, arg)
+		if id == envsId && !hasValue && i+1 < len(prepared) {
+			i++
+			ownArgs = append(ownArgs, prepared[i])
+		}
 	}
+	return own.Parse(ownArgs)
+}
 
-	flagStorage := make(map[string]int, len(udotEnv.Config.EnvFlags)+len(udotEnv.Config.OverloadFlags))
-	for _, v := range udotEnv.Config.EnvFlags {
-		flag.Var(&udotEnv.EnvParam, v, "help message for flag n")
-		flagStorage[v] = envsId
+// Args returns the command-line arguments, without the program name, that
+// remain once the env and overload flags are removed. It is only populated
+// by New when `PreserveArgs` is set in the config.
+func (ue *UdotEnv) Args() []string {
+	return ue.args
+}
+
+// NewWithFlagSet creates a new instance of UdotEnv and registers its flags
+// on fs instead of the global flag set. If config is nil, the default
+// configuration is used. If the flags of config cannot be registered on fs
+// (see Config.Validate), it panics with a *ConfigError, or reports it as set
+// by the `ErrorHandling` of config. Unlike New, it neither touches `os.Args`
+// nor parses the flags; that is left to the caller:
+//
+//	fs := flag.NewFlagSet("app", flag.ExitOnError)
+//	udotEnv := udotenv.NewWithFlagSet(fs, nil)
+//	fs.Parse(udotEnv.PrepareArgs(os.Args[1:]))
+//	err := udotEnv.Load()
+func NewWithFlagSet(fs *flag.FlagSet, config *Config) *UdotEnv {
+	udotEnv, err := newWithFlagSet(fs, config)
+	if err != nil {
+		udotEnv.failNew(err)
 	}
+	return udotEnv
+}
 
-	for _, v := range udotEnv.Config.OverloadFlags {
-		flag.BoolVar(&udotEnv.OverloadParam, v, udotEnv.Config.OverloadByDefault, "help message for flag n")
-		flagStorage[v] = overloadId
+// newWithFlagSet implements NewWithFlagSet and NewWithOptions. It returns the
+// error of the config instead of reporting it, along with the instance, whose
+// flags are then not registered.
+func newWithFlagSet(fs *flag.FlagSet, config *Config) (*UdotEnv, error) {
+	udotEnv := &UdotEnv{Config: config}
+	if udotEnv.Config == nil {
+		udotEnv.Config = GetDefaultConfig()
+	} else if udotEnv.Config.DefaultEnvPath == "" {
+		udotEnv.Config.DefaultEnvPath = defaultEnvPath
 	}
 
+	if err := udotEnv.Config.validate(fs); err != nil {
+		return udotEnv, err
+	}
+	udotEnv.register(fs)
+	udotEnv.flagSet = fs
+	return udotEnv, nil
+}
+
+// Parse parses the command-line arguments, `os.Args` without the program
+// name, with the flag set the flags were registered on by New or
+// NewWithFlagSet, once prepared by PrepareArgs. With `PreserveArgs` set in
+// the config, only the arguments returned by Args are parsed by the flag set,
+// the env and overload flags being parsed apart.
+//
+//	udotEnv := udotenv.New(false)
+//	if err := udotEnv.Parse(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	err := udotEnv.Load()
+func (ue *UdotEnv) Parse() error {
+	if ue.err != nil {
+		return ue.err
+	}
+	if ue.flagSet == nil {
+		return errors.New("no flag set to parse: create the instance with New or NewWithFlagSet")
+	}
 	if len(os.Args) <= 1 {
-		return
+		return ue.flagSet.Parse(nil)
+	}
+
+	if ue.Config.PreserveArgs {
+		if ue.args == nil {
+			if err := ue.extractArgs(os.Args[0], os.Args[1:]); err != nil {
+				return err
+			}
+		}
+		return ue.flagSet.Parse(ue.args)
+	}
+	args, err := ue.prepareArgs(os.Args[1:])
+	if err != nil {
+		return err
+	}
+	return ue.flagSet.Parse(args)
+}
+
+// MustParse is like Parse but panics if the arguments cannot be parsed.
+func (ue *UdotEnv) MustParse() {
+	if err := ue.Parse(); err != nil {
+		panic(err)
+	}
+}
+
+// register defines the env, overload and dry-run flags on fs. The first name
+// of each list gets the usage message, the others are described as its
+// aliases.
+func (ue *UdotEnv) register(fs *flag.FlagSet) {
+	envUsage := cmp.Or(ue.Config.EnvFlagUsage, defaultEnvFlagUsage)
+	for i, v := range ue.Config.EnvFlags {
+		fs.Var(&ue.EnvParam, v, aliasUsage(ue.Config.EnvFlags, i, envUsage))
+	}
+
+	overloadUsage := cmp.Or(ue.Config.OverloadFlagUsage, defaultOverloadFlagUsage)
+	for i, v := range ue.Config.OverloadFlags {
+		fs.BoolVar(&ue.OverloadParam, v, ue.Config.OverloadByDefault, aliasUsage(ue.Config.OverloadFlags, i, overloadUsage))
+	}
+
+	for i, v := range ue.Config.DryRunFlags {
+		fs.BoolVar(&ue.DryRunParam, v, false, aliasUsage(ue.Config.DryRunFlags, i, dryRunFlagUsage))
+	}
+}
+
+// aliasUsage returns the usage message of the i-th flag of names: usage for
+// the first one and a reference to it for its aliases.
+func aliasUsage(names []string, i int, usage string) string {
+	if i == 0 {
+		return usage
 	}
+	return "alias for -" + names[0]
+}
 
-	newArgs := make([]string, 1, len(os.Args)+1) // add 1 for case if envParam passed without a value
-	newArgs[0] = os.Args[0]
+// flagIds maps the names of the env, overload and dry-run flags to their
+// parameter.
+func (ue *UdotEnv) flagIds() map[string]int {
+	ids := make(map[string]int, len(ue.Config.EnvFlags)+len(ue.Config.OverloadFlags)+len(ue.Config.DryRunFlags))
+	for _, v := range ue.Config.EnvFlags {
+		ids[v] = envsId
+	}
+	for _, v := range ue.Config.OverloadFlags {
+		ids[v] = overloadId
+	}
+	for _, v := range ue.Config.DryRunFlags {
+		ids[v] = dryRunId
+	}
+	return ids
+}
+
+// PrepareArgs returns a copy of the command-line arguments args (without the
+// program name) that is ready to be parsed by a flag set. An env flag passed
+// without a value gets the `DefaultEnvPath` of the config as its value, so
+// that `-e` alone loads the default file. Flags are recognised in the `-flag`,
+// `--flag` and `=`-joined `--flag=value` forms, and single-letter flags may be
+// combined, as in `-oe`, which is split into `-o -e`. The arguments after the
+// `--` terminator are left untouched, as they belong to the program or to a
+// command it runs.
+//
+// PrepareArgs panics if multiple flags for the same parameter are passed.
+func (ue *UdotEnv) PrepareArgs(args []string) []string {
+	newArgs, err := ue.prepareArgs(args)
+	if err != nil {
+		panic(err.Error())
+	}
+	return newArgs
+}
+
+func (ue *UdotEnv) prepareArgs(args []string) ([]string, error) {
+	flagStorage := ue.flagIds()
+
+	newArgs := make([]string, 0, len(args)+1) // add 1 for case if envParam passed without a value
 
 	passedParams := make(map[int]bool, 2)
-	for i, argName := range os.Args[1:] {
-		newArgs = append(newArgs, argName)
-		if !strings.HasPrefix(argName, "-") || len(argName) < 2 {
-			continue
+	for i, arg := range args {
+		if arg == "--" {
+			return append(newArgs, args[i:]...), nil
 		}
 
-		argId, ok := flagStorage[argName[1:]]
-		if !ok {
-			argId, ok = flagStorage[argName[2:]]
+		flags := splitFlags(arg, flagStorage)
+		if flags == nil {
+			newArgs = append(newArgs, arg)
+			continue
 		}
-		_, passed := passedParams[argId]
 
-		if ok && passed {
-			panic("only one flag per param must be passed")
-		} else if ok && argId != envsId {
-			passedParams[argId] = true
-		}
+		for j, f := range flags {
+			argId := flagStorage[f.name]
+			if passedParams[argId] {
+				return nil, errors.New("only one flag per param must be passed")
+			} else if argId != envsId {
+				passedParams[argId] = true
+			}
 
-		if (argId == envsId) &&
-			((len(os.Args)-2 == i) ||
-				((len(os.Args)-2 > i) && (strings.HasPrefix(os.Args[i+2], "-")))) {
-			newArgs = append(newArgs, udotEnv.Config.DefaultEnvPath)
+			if argId == envsId && f.hasValue && f.value == "" {
+				f.value = ue.defaultPath()
+			}
+			newArgs = append(newArgs, f.String())
+
+			if argId == envsId && !f.hasValue &&
+				(j < len(flags)-1 || len(args)-1 == i || strings.HasPrefix(args[i+1], "-")) {
+				newArgs = append(newArgs, ue.defaultPath())
+			}
 		}
 	}
-	os.Args = newArgs
+	return newArgs, nil
+}
 
-	if parseFlags {
-		flag.Parse()
+// argFlag is a flag of the command-line arguments.
+type argFlag struct {
+	dashes   string
+	name     string
+	value    string
+	hasValue bool
+}
+
+func (f argFlag) String() string {
+	if f.hasValue {
+		return f.dashes + f.name + "=" + f.value
 	}
-	return
+	return f.dashes + f.name
+}
+
+// splitFlags returns the udotenv flags of the argument arg, in the `-flag`,
+// `--flag`, `-flag=value` or `--flag=value` forms, or nil if it is not one.
+// Single-letter flags may be combined, as in `-oe`, in which case one flag is
+// returned per letter, the value going to the last one.
+func splitFlags(arg string, ids map[string]int) []argFlag {
+	if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+		return nil
+	}
+	f := argFlag{dashes: "-", name: arg[1:]}
+	if strings.HasPrefix(f.name, "-") {
+		f.dashes, f.name = "--", f.name[1:]
+	}
+	f.name, f.value, f.hasValue = strings.Cut(f.name, "=")
+	if _, ok := ids[f.name]; ok {
+		return []argFlag{f}
+	}
+	if f.dashes != "-" || len(f.name) < 2 {
+		return nil
+	}
+
+	var flags []argFlag
+	for _, r := range f.name {
+		if _, ok := ids[string(r)]; !ok {
+			return nil
+		}
+		flags = append(flags, argFlag{dashes: "-", name: string(r)})
+	}
+	last := &flags[len(flags)-1]
+	last.value, last.hasValue = f.value, f.hasValue
+	return flags
 }
@@ -1,8 +1,20 @@
 package udotenv
 
 import (
+	"context"
+	"flag"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +26,9 @@ func TestGetDefaultConfig(t *testing.T) {
 	assert.NotNil(t, config)
 	assert.Equal(t, []string{"envs", "e"}, config.EnvFlags)
 	assert.Equal(t, []string{"env-overload", "eo", "o"}, config.OverloadFlags)
+	assert.Equal(t, []string{"env-dry-run"}, config.DryRunFlags)
 	assert.Equal(t, defaultEnvPath, config.DefaultEnvPath)
+	assert.Equal(t, ".env.defaults", config.DefaultsFile)
 	assert.False(t, config.OverloadByDefault)
 }
 
@@ -54,8 +68,142 @@ func TestNew_MultipleConfigsPanics(t *testing.T) {
 	New(false, GetDefaultConfig(), GetDefaultConfig())
 }
 
+func TestNew_PreserveArgs(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	os.Args = []string{"app", "-v", "-pa-env", "--pa-o", "sub", "--pa-env=b.env", "-x"}
+	original := append([]string{}, os.Args...)
+
+	udotEnv := New(false, &Config{
+		EnvFlags:      []string{"pa-env"},
+		OverloadFlags: []string{"pa-o"},
+		PreserveArgs:  true,
+	})
+
+	assert.Equal(t, original, os.Args)
+	assert.Equal(t, stringSlice{defaultEnvPath, "b.env"}, udotEnv.EnvParam)
+	assert.True(t, udotEnv.OverloadParam)
+	assert.Equal(t, []string{"-v", "sub", "-x"}, udotEnv.Args())
+}
+
+func TestUdotEnv_Parse(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"app", "-v", "-oe", "x.env", "rest"}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "")
+	udotEnv := NewWithFlagSet(fs, nil)
+	assert.NoError(t, udotEnv.Parse())
+	assert.True(t, *verbose)
+	assert.True(t, udotEnv.OverloadParam)
+	assert.Equal(t, []string{"x.env"}, udotEnv.Files())
+	assert.Equal(t, []string{"rest"}, fs.Args())
+
+	os.Args = []string{"app", "-unknown"}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	udotEnv = NewWithFlagSet(fs, nil)
+	assert.Error(t, udotEnv.Parse())
+	assert.Panics(t, udotEnv.MustParse)
+
+	assert.Error(t, (&UdotEnv{Config: GetDefaultConfig()}).Parse())
+}
+
+func TestUdotEnv_ParsePreserveArgs(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = []string{"app", "-v", "-e", "a.env", "sub"}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "")
+	config := GetDefaultConfig()
+	config.PreserveArgs = true
+	udotEnv := NewWithFlagSet(fs, config)
+	assert.NoError(t, udotEnv.Parse())
+	assert.True(t, *verbose)
+	assert.Equal(t, []string{"a.env"}, udotEnv.Files())
+	assert.Equal(t, []string{"sub"}, fs.Args())
+}
+
+func TestNewWithFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	udotEnv := NewWithFlagSet(fs, nil)
+
+	assert.NotNil(t, fs.Lookup("envs"))
+	assert.NotNil(t, fs.Lookup("eo"))
+
+	err := fs.Parse(udotEnv.PrepareArgs([]string{"-e", "--eo", "-envs", "custom.env", "rest"}))
+	assert.NoError(t, err)
+	assert.Equal(t, stringSlice{defaultEnvPath, "custom.env"}, udotEnv.EnvParam)
+	assert.True(t, udotEnv.OverloadParam)
+	assert.Equal(t, []string{"rest"}, fs.Args())
+}
+
+func TestNewWithFlagSet_Usage(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	NewWithFlagSet(fs, nil)
+
+	assert.Equal(t, defaultEnvFlagUsage, fs.Lookup("envs").Usage)
+	assert.Equal(t, "alias for -envs", fs.Lookup("e").Usage)
+	assert.Equal(t, defaultOverloadFlagUsage, fs.Lookup("env-overload").Usage)
+	assert.Equal(t, "alias for -env-overload", fs.Lookup("o").Usage)
+
+	config := GetDefaultConfig()
+	config.EnvFlagUsage = "read `file`"
+	config.OverloadFlagUsage = "override"
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	NewWithFlagSet(fs, config)
+	assert.Equal(t, "read `file`", fs.Lookup("envs").Usage)
+	assert.Equal(t, "override", fs.Lookup("env-overload").Usage)
+}
+
+func TestPrepareArgs(t *testing.T) {
+	udotEnv := &UdotEnv{Config: GetDefaultConfig()}
+
+	assert.Equal(t, []string{"-e", ".env"}, udotEnv.PrepareArgs([]string{"-e"}))
+	assert.Equal(t, []string{"-e", ".env", "-o"}, udotEnv.PrepareArgs([]string{"-e", "-o"}))
+	assert.Equal(t, []string{"-e", "a.env", "x"}, udotEnv.PrepareArgs([]string{"-e", "a.env", "x"}))
+	assert.Panics(t, func() {
+		udotEnv.PrepareArgs([]string{"-o", "--env-overload"})
+	})
+}
+
+func TestPrepareArgs_Terminator(t *testing.T) {
+	udotEnv := &UdotEnv{Config: GetDefaultConfig()}
+
+	assert.Equal(t, []string{"-e", ".env", "--", "cmd", "-e", "-o", "-o"}, udotEnv.PrepareArgs([]string{"-e", "--", "cmd", "-e", "-o", "-o"}))
+	assert.Equal(t, []string{"--", "-oe"}, udotEnv.PrepareArgs([]string{"--", "-oe"}))
+}
+
+func TestPrepareArgs_JoinedValues(t *testing.T) {
+	udotEnv := &UdotEnv{Config: GetDefaultConfig()}
+
+	assert.Equal(t, []string{"--envs=.env.prod", "x"}, udotEnv.PrepareArgs([]string{"--envs=.env.prod", "x"}))
+	assert.Equal(t, []string{"-e=a.env", "-e", ".env"}, udotEnv.PrepareArgs([]string{"-e=a.env", "-e"}))
+	assert.Equal(t, []string{"-e=.env"}, udotEnv.PrepareArgs([]string{"-e="}))
+	assert.Equal(t, []string{"-o", "-e", ".env"}, udotEnv.PrepareArgs([]string{"-oe"}))
+	assert.Equal(t, []string{"-o", "-e=a.env"}, udotEnv.PrepareArgs([]string{"-oe=a.env"}))
+	assert.Equal(t, []string{"-eo"}, udotEnv.PrepareArgs([]string{"-eo"}))
+	assert.Equal(t, []string{"-ox"}, udotEnv.PrepareArgs([]string{"-ox"}))
+	assert.Panics(t, func() {
+		udotEnv.PrepareArgs([]string{"--env-overload=true", "-o"})
+	})
+	assert.Panics(t, func() {
+		udotEnv.PrepareArgs([]string{"-o", "-oe"})
+	})
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	udotEnv = NewWithFlagSet(fs, nil)
+	assert.NoError(t, fs.Parse(udotEnv.PrepareArgs([]string{"-oe=a.env", "--envs=b.env", "rest"})))
+	assert.Equal(t, []string{"a.env", "b.env"}, udotEnv.Files())
+	assert.True(t, udotEnv.OverloadParam)
+	assert.Equal(t, []string{"rest"}, fs.Args())
+}
+
 func TestLoad_NoEnvParam(t *testing.T) {
-	udotEnv := &udotEnvType{}
+	udotEnv := &UdotEnv{}
 
 	assert.NotPanics(t, func() {
 		udotEnv.Load()
@@ -66,7 +214,7 @@ func TestLoad_WithEnvParam(t *testing.T) {
 	_ = godotenv.Write(map[string]string{"TEST_KEY": "TEST_VALUE"}, ".test.env")
 	defer os.Remove(".test.env")
 
-	udotEnv := &udotEnvType{
+	udotEnv := &UdotEnv{
 		EnvParam:      stringSlice{".test.env"},
 		OverloadParam: false,
 	}
@@ -84,7 +232,7 @@ func TestLoad_WithOverloadParam(t *testing.T) {
 
 	os.Setenv("TEST_KEY", "OLD_VALUE")
 
-	udotEnv := &udotEnvType{
+	udotEnv := &UdotEnv{
 		EnvParam:      stringSlice{".test.env"},
 		OverloadParam: true,
 	}
@@ -95,3 +243,451 @@ func TestLoad_WithOverloadParam(t *testing.T) {
 
 	assert.Equal(t, "NEW_VALUE", os.Getenv("TEST_KEY"))
 }
+
+func TestLoad_MaxKeysExceeded(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"LIMIT_A": "1", "LIMIT_B": "2", "LIMIT_C": "3"}, ".test.env")
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{MaxKeys: 2},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.EqualError(t, udotEnv.Load(), "too many keys to load: 3, limit is 2")
+
+	_, ok := os.LookupEnv("LIMIT_A")
+	assert.False(t, ok)
+}
+
+func TestLoad_MaxKeysIgnoresExistingKeys(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"LIMIT_D": "1", "LIMIT_E": "2"}, ".test.env")
+	defer os.Remove(".test.env")
+
+	os.Setenv("LIMIT_D", "0")
+	defer os.Unsetenv("LIMIT_D")
+	defer os.Unsetenv("LIMIT_E")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{MaxKeys: 1},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "0", os.Getenv("LIMIT_D"))
+	assert.Equal(t, "2", os.Getenv("LIMIT_E"))
+}
+
+func TestLoad_MaxEnvBytesExceeded(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"LIMIT_F": "abcdefghij"}, ".test.env")
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{MaxEnvBytes: 10},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.EqualError(t, udotEnv.Load(), "environment too large to load: 18 bytes in 1 keys, limit is 10 bytes")
+
+	_, ok := os.LookupEnv("LIMIT_F")
+	assert.False(t, ok)
+}
+
+func TestLoad_LimitsContinueOnError(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"LIMIT_G": "abcdefghij", "LIMIT_H": "1"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("LIMIT_G")
+	defer os.Unsetenv("LIMIT_H")
+
+	var warnings []string
+	udotEnv := &UdotEnv{
+		Config: &Config{MaxKeys: 1, MaxEnvBytes: 10, ContinueOnError: true, OnWarning: func(msg string) {
+			warnings = append(warnings, msg)
+		}},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, []string{"too many keys to load: 2, limit is 1"}, warnings)
+	assert.Equal(t, "abcdefghij", os.Getenv("LIMIT_G"))
+	assert.Equal(t, "1", os.Getenv("LIMIT_H"))
+
+	warnings = nil
+	udotEnv.Config.MaxKeys = 0
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, []string{"environment too large to load: 27 bytes in 2 keys, limit is 10 bytes"}, warnings)
+}
+
+func TestLoad_KeyPattern(t *testing.T) {
+	_ = godotenv.Write(map[string]string{
+		"DB_HOST":    "db",
+		"CACHE_HOST": "cache",
+		"APP_HOST":   "app",
+	}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("DB_HOST")
+	defer os.Unsetenv("CACHE_HOST")
+
+	var warnings []string
+	udotEnv := &UdotEnv{
+		Config: &Config{KeyPattern: regexp.MustCompile(`^(DB|CACHE)_`), OnWarning: func(msg string) {
+			warnings = append(warnings, msg)
+		}},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, []string{"APP_HOST from .test.env does not match KeyPattern and is not loaded"}, warnings)
+
+	assert.Equal(t, "db", os.Getenv("DB_HOST"))
+	assert.Equal(t, "cache", os.Getenv("CACHE_HOST"))
+	_, ok := os.LookupEnv("APP_HOST")
+	assert.False(t, ok)
+}
+
+func TestLoad_Prefix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("PFXAPP_PORT=8080\nPFXAPP_URL=http://localhost:${PFXAPP_PORT}\nPFXOTHER_PORT=9090\nPFXAPP_=empty\n"), 0o644)
+	for _, k := range []string{"PFXAPP_PORT", "PFXAPP_URL", "PFX_PORT", "PFX_URL", "PFXAPP_"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{Config: &Config{Prefix: "PFXAPP_", Expand: true}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "8080", os.Getenv("PFXAPP_PORT"))
+	assert.Equal(t, "http://localhost:8080", os.Getenv("PFXAPP_URL"))
+	_, ok := os.LookupEnv("PFXOTHER_PORT")
+	assert.False(t, ok)
+
+	udotEnv = &UdotEnv{Config: &Config{Prefix: "PFXAPP_", StripPrefix: true, AddPrefix: "PFX_"}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "8080", os.Getenv("PFX_PORT"))
+	assert.Equal(t, "http://localhost:8080", os.Getenv("PFX_URL"))
+	_, ok = os.LookupEnv("PFX_")
+	assert.False(t, ok)
+
+	vars, err := (&UdotEnv{Config: &Config{AddPrefix: "NS_"}, EnvParam: stringSlice{path}}).Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "9090", vars["NS_PFXOTHER_PORT"])
+	assert.Len(t, vars, 4)
+}
+
+func TestLoad_Aliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("ALIAS_DB_URL=postgres://legacy\nALIAS_OLD_HOST=old\nALIAS_HOST=new\n"), 0o644)
+	for _, k := range []string{"ALIAS_DATABASE_URL", "ALIAS_HOST"} {
+		defer os.Unsetenv(k)
+	}
+
+	var warnings []string
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			Aliases:   map[string]string{"ALIAS_DB_URL": "ALIAS_DATABASE_URL", "ALIAS_OLD_HOST": "ALIAS_HOST"},
+			OnWarning: func(msg string) { warnings = append(warnings, msg) },
+		},
+		EnvParam: stringSlice{path},
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "postgres://legacy", os.Getenv("ALIAS_DATABASE_URL"))
+	assert.Equal(t, "new", os.Getenv("ALIAS_HOST"))
+	_, ok := os.LookupEnv("ALIAS_DB_URL")
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		path + ": ALIAS_DB_URL is deprecated, use ALIAS_DATABASE_URL",
+		path + ": ALIAS_OLD_HOST is deprecated and ignored, since ALIAS_HOST is defined",
+	}, warnings)
+}
+
+func TestLoad_ProtectedKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	secret := filepath.Join(dir, "secret")
+	_ = os.WriteFile(secret, []byte("from file"), 0o644)
+	_ = os.WriteFile(path, []byte("PROTECTED_HOME=/tmp\nPROTECTED_NEW=1\nPROTECTED_OTHER=file\nPROTECTED_REF_FILE="+secret+"\n"), 0o644)
+	t.Setenv("PROTECTED_HOME", "/home/user")
+	t.Setenv("PROTECTED_OTHER", "env")
+	t.Setenv("PROTECTED_REF", "env")
+	for _, k := range []string{"PROTECTED_NEW", "PROTECTED_REF_FILE"} {
+		defer os.Unsetenv(k)
+	}
+
+	var warnings []string
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			ProtectedKeys: []string{"PROTECTED_HOME", "PROTECTED_NEW", "PROTECTED_REF"},
+			FileRefSuffix: "_FILE",
+			KeyPattern:    regexp.MustCompile("^PROTECTED_"),
+			OnWarning:     func(msg string) { warnings = append(warnings, msg) },
+		},
+		EnvParam:      stringSlice{path},
+		OverloadParam: true,
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "/home/user", os.Getenv("PROTECTED_HOME"))
+	assert.Equal(t, "1", os.Getenv("PROTECTED_NEW"))
+	assert.Equal(t, "file", os.Getenv("PROTECTED_OTHER"))
+	assert.Equal(t, "env", os.Getenv("PROTECTED_REF"))
+	assert.Equal(t, []string{
+		"PROTECTED_HOME is protected and not overwritten",
+		"PROTECTED_REF is protected and not overwritten",
+	}, warnings)
+}
+
+func TestLoad_OverloadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("ONLY_LOG_LEVEL=debug\nONLY_FEATURE_X=on\nONLY_PORT=8080\nONLY_NEW=1\n"), 0o644)
+	for _, k := range []string{"ONLY_LOG_LEVEL", "ONLY_FEATURE_X", "ONLY_PORT"} {
+		t.Setenv(k, "env")
+	}
+	defer os.Unsetenv("ONLY_NEW")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{OverloadOnly: []string{"ONLY_LOG_LEVEL", "ONLY_FEATURE_*"}},
+		EnvParam: stringSlice{path},
+	}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "debug", os.Getenv("ONLY_LOG_LEVEL"))
+	assert.Equal(t, "on", os.Getenv("ONLY_FEATURE_X"))
+	assert.Equal(t, "env", os.Getenv("ONLY_PORT"))
+	assert.Equal(t, "1", os.Getenv("ONLY_NEW"))
+
+	udotEnv.OverloadParam = true
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "env", os.Getenv("ONLY_PORT"))
+}
+
+func TestLoad_ExpandOverloadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("RULES_HOME=/file\nRULES_PORT=1\nRULES_X=${RULES_HOME}\nRULES_Y=${RULES_PORT}\n"), 0o644)
+	t.Setenv("RULES_HOME", "/env")
+	t.Setenv("RULES_PORT", "2")
+	for _, k := range []string{"RULES_X", "RULES_Y"} {
+		defer os.Unsetenv(k)
+	}
+
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			Expand:        true,
+			ProtectedKeys: []string{"RULES_HOME"},
+			OverloadOnly:  []string{"RULES_HOME", "RULES_X", "RULES_Y"},
+		},
+		EnvParam:      stringSlice{path},
+		OverloadParam: true,
+	}
+	assert.NoError(t, udotEnv.Load())
+	// references resolve to the values the variables end up with
+	assert.Equal(t, "/env", os.Getenv("RULES_HOME"))
+	assert.Equal(t, "/env", os.Getenv("RULES_X"))
+	assert.Equal(t, "2", os.Getenv("RULES_PORT"))
+	assert.Equal(t, "2", os.Getenv("RULES_Y"))
+}
+
+func TestLoad_DefaultDefaultsFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile(".env.defaults", []byte("STACK_A=default\nSTACK_B=default\n"), 0o644)
+	_ = os.WriteFile(".env", []byte("STACK_B=file\n"), 0o644)
+	defer os.Unsetenv("STACK_A")
+	defer os.Unsetenv("STACK_B")
+
+	udotEnv := &UdotEnv{Config: GetDefaultConfig(), EnvParam: stringSlice{".env"}, OverloadParam: true}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "default", os.Getenv("STACK_A"))
+	assert.Equal(t, "file", os.Getenv("STACK_B"))
+}
+
+func TestLoad_DefaultsFile(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"DEFAULTS_A": "default", "DEFAULTS_B": "default"}, ".test.defaults.env")
+	defer os.Remove(".test.defaults.env")
+	_ = godotenv.Write(map[string]string{"DEFAULTS_B": "file"}, ".test.env")
+	defer os.Remove(".test.env")
+	defer os.Unsetenv("DEFAULTS_A")
+	defer os.Unsetenv("DEFAULTS_B")
+
+	udotEnv := &UdotEnv{
+		Config:        &Config{DefaultsFile: ".test.defaults.env"},
+		EnvParam:      stringSlice{".test.env"},
+		OverloadParam: true,
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "default", os.Getenv("DEFAULTS_A"))
+	assert.Equal(t, "file", os.Getenv("DEFAULTS_B"))
+}
+
+func TestLoad_DefaultsFileWithoutFlags(t *testing.T) {
+	_ = godotenv.Write(map[string]string{"DEFAULTS_C": "default", "DEFAULTS_D": "default"}, ".test.defaults.env")
+	defer os.Remove(".test.defaults.env")
+	defer os.Unsetenv("DEFAULTS_C")
+
+	os.Setenv("DEFAULTS_D", "env")
+	defer os.Unsetenv("DEFAULTS_D")
+
+	udotEnv := &UdotEnv{
+		Config:        &Config{DefaultsFile: ".test.defaults.env"},
+		OverloadParam: true,
+	}
+
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "default", os.Getenv("DEFAULTS_C"))
+	assert.Equal(t, "env", os.Getenv("DEFAULTS_D"))
+}
+
+func TestLoad_MissingDefaultsFile(t *testing.T) {
+	udotEnv := &UdotEnv{
+		Config: &Config{DefaultsFile: ".missing.defaults.env"},
+	}
+
+	assert.NoError(t, udotEnv.Load())
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	udotEnv := &UdotEnv{EnvParam: stringSlice{".missing.env"}}
+
+	err := udotEnv.Load()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorContains(t, err, "error loading file '.missing.env'")
+}
+
+func TestLoad_PanicOnError(t *testing.T) {
+	udotEnv := &UdotEnv{
+		Config:   &Config{PanicOnError: true},
+		EnvParam: stringSlice{".missing.env"},
+	}
+
+	assert.PanicsWithValue(t, "error loading file '.missing.env': open .missing.env: no such file or directory", func() {
+		udotEnv.Load()
+	})
+}
+
+func TestFilesAndOverload(t *testing.T) {
+	var loader Loader = &UdotEnv{
+		EnvParam:      stringSlice{"a.env", "b.env"},
+		OverloadParam: true,
+	}
+
+	files := loader.Files()
+	assert.Equal(t, []string{"a.env", "b.env"}, files)
+	files[0] = "changed.env"
+	assert.Equal(t, []string{"a.env", "b.env"}, loader.Files())
+	assert.True(t, loader.Overload())
+}
+
+func TestLoad_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("CONC_A=1\nCONC_B=2\n"), 0o644)
+	defer os.Unsetenv("CONC_A")
+	defer os.Unsetenv("CONC_B")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, udotEnv.Load())
+			_, _ = udotEnv.Reload()
+			_, _ = udotEnv.Changed()
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := udotEnv.GetInt("CONC_A")
+			assert.NoError(t, err)
+			assert.Len(t, udotEnv.LoadedKeys(), 2)
+			_, _ = udotEnv.ExportScript()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoad_Idempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("IDEM_A=1\n"), 0o644)
+	defer os.Unsetenv("IDEM_A")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "1", os.Getenv("IDEM_A"))
+
+	changes, err := udotEnv.Reload()
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestLoadContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("CTX_A=1\n"), 0o644)
+	defer os.Unsetenv("CTX_A")
+
+	type ctxKey struct{}
+	var got any
+	src := SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		got = ctx.Value(ctxKey{})
+		return nil, ctx.Err()
+	})
+	udotEnv := &UdotEnv{Config: &Config{Sources: []Source{src}}, EnvParam: stringSlice{path}}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	assert.NoError(t, udotEnv.LoadContext(ctx))
+	assert.Equal(t, "value", got)
+	assert.Equal(t, "1", os.Getenv("CTX_A"))
+
+	_ = os.WriteFile(path, []byte("CTX_A=2\n"), 0o644)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, udotEnv.LoadContext(ctx), context.Canceled)
+	assert.Equal(t, "1", os.Getenv("CTX_A"))
+}
+
+func TestLoadContext_Deadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{server.URL + "/app.env"}}
+	assert.ErrorIs(t, udotEnv.LoadContext(ctx), context.DeadlineExceeded)
+}
+
+func TestLoad_Logger(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	_ = os.WriteFile(path, []byte("LOG_A=secret\nLOG_B=2\nOTHER_LOG=1\n"), 0o644)
+	t.Setenv("LOG_B", "env")
+	defer os.Unsetenv("LOG_A")
+
+	var buf strings.Builder
+	removeTime := func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return a
+	}
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: removeTime}))
+	udotEnv := &UdotEnv{
+		Config: &Config{
+			Logger:        logger,
+			OptionalFiles: []string{".missing.env"},
+			KeyPattern:    regexp.MustCompile("^LOG_"),
+		},
+		EnvParam: stringSlice{path, ".missing.env"},
+	}
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, strings.Join([]string{
+		`level=DEBUG msg="env file read" path=` + path + ` keys=3`,
+		`level=DEBUG msg="missing env file skipped" path=.missing.env`,
+		`level=WARN msg="OTHER_LOG from ` + path + ` does not match KeyPattern and is not loaded"`,
+		`level=DEBUG msg="variable already set, skipped" key=LOG_B source=` + path,
+		`level=DEBUG msg="variable set" key=LOG_A source=` + path,
+	}, "\n")+"\n", buf.String())
+	assert.NotContains(t, buf.String(), "secret")
+}
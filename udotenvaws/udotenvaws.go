@@ -0,0 +1,163 @@
+// Package udotenvaws loads variables from AWS Secrets Manager and SSM
+// Parameter Store. Importing it registers two schemes for the env flags:
+//
+//   - aws-sm://NAME loads the secret NAME. A secret holding a JSON object
+//     yields one variable per field, nested objects being flattened with
+//     underscores; any other secret yields a single variable named after the
+//     last segment of NAME.
+//   - ssm:///PATH loads the parameter PATH, and ssm:///PATH/* loads all the
+//     parameters under PATH, recursively, named after their path relative to
+//     PATH.
+//
+// Names are converted to variable names with udotenv.EnvKey, which
+// upper-cases them and replaces the characters other than letters, digits
+// and underscores with underscores, so that `db/host` becomes `DB_HOST`.
+//
+// The clients use the default AWS configuration: credentials come from the
+// environment, the shared config files or the IAM role of the instance.
+package udotenvaws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+const (
+	SecretsManagerScheme = "aws-sm"
+	ParameterStoreScheme = "ssm"
+)
+
+func init() {
+	udotenv.RegisterScheme(SecretsManagerScheme, func(ref string) (udotenv.Source, error) {
+		return lazy(func(cfg aws.Config) udotenv.Source {
+			return SecretsManager(secretsmanager.NewFromConfig(cfg), strings.TrimPrefix(ref, SecretsManagerScheme+"://"))
+		}), nil
+	})
+	udotenv.RegisterScheme(ParameterStoreScheme, func(ref string) (udotenv.Source, error) {
+		return lazy(func(cfg aws.Config) udotenv.Source {
+			return ParameterStore(ssm.NewFromConfig(cfg), strings.TrimPrefix(ref, ParameterStoreScheme+"://"))
+		}), nil
+	})
+}
+
+// lazy returns a source that loads the default AWS configuration when it is
+// fetched and delegates to the source created by newSource.
+func lazy(newSource func(cfg aws.Config) udotenv.Source) udotenv.Source {
+	return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading AWS config: %w", err)
+		}
+		return newSource(cfg).Fetch(ctx)
+	})
+}
+
+// SecretsManagerAPI is the part of the Secrets Manager client used by
+// SecretsManager.
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SSMAPI is the part of the SSM client used by ParameterStore.
+type SSMAPI interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput,
+		optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+	ssm.GetParametersByPathAPIClient
+}
+
+// SecretsManager returns a source loading the secret name with client. See
+// the package documentation for how the secret is turned into variables.
+func SecretsManager(client SecretsManagerAPI, name string) udotenv.Source {
+	return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+		if err != nil {
+			return nil, err
+		}
+
+		value := aws.ToString(out.SecretString)
+		if out.SecretString == nil {
+			value = string(out.SecretBinary)
+		}
+
+		var fields map[string]any
+		dec := json.NewDecoder(strings.NewReader(value))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil || dec.More() {
+			return map[string]string{udotenv.EnvKey(path.Base(name)): value}, nil
+		}
+
+		vars := make(map[string]string)
+		flatten(vars, "", fields)
+		return vars, nil
+	})
+}
+
+// ParameterStore returns a source loading the parameter at name with client,
+// or all the parameters under it if name ends with "/*". SecureString
+// parameters are decrypted.
+func ParameterStore(client SSMAPI, name string) udotenv.Source {
+	return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		prefix, all := strings.CutSuffix(name, "/*")
+		if !all {
+			out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+				Name:           aws.String(name),
+				WithDecryption: aws.Bool(true),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{udotenv.EnvKey(path.Base(name)): aws.ToString(out.Parameter.Value)}, nil
+		}
+
+		vars := make(map[string]string)
+		pages := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+			Path:           aws.String(prefix),
+			Recursive:      aws.Bool(true),
+			WithDecryption: aws.Bool(true),
+		})
+		for pages.HasMorePages() {
+			page, err := pages.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range page.Parameters {
+				key := strings.TrimPrefix(strings.TrimPrefix(aws.ToString(p.Name), prefix), "/")
+				vars[udotenv.EnvKey(key)] = aws.ToString(p.Value)
+			}
+		}
+		return vars, nil
+	})
+}
+
+// flatten adds the fields of a JSON object to vars, joining the names of
+// nested objects with underscores.
+func flatten(vars map[string]string, prefix string, fields map[string]any) {
+	for name, v := range fields {
+		key := udotenv.EnvKey(name)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(vars, key, v)
+		case string:
+			vars[key] = v
+		case nil:
+			vars[key] = ""
+		default:
+			b, _ := json.Marshal(v)
+			vars[key] = string(b)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package udotenvaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSecretsManager map[string]string
+
+func (f fakeSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput,
+	optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(f[aws.ToString(params.SecretId)])}, nil
+}
+
+type fakeSSM struct {
+	pages [][]types.Parameter
+}
+
+func (f *fakeSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput,
+	optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Value: aws.String("single")}}, nil
+}
+
+func (f *fakeSSM) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput,
+	optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	i := 0
+	if params.NextToken != nil {
+		i = 1
+	}
+	out := &ssm.GetParametersByPathOutput{Parameters: f.pages[i]}
+	if i+1 < len(f.pages) {
+		out.NextToken = aws.String("next")
+	}
+	return out, nil
+}
+
+func TestSecretsManager(t *testing.T) {
+	client := fakeSecretsManager{
+		"myapp/prod":  `{"db": {"host": "localhost", "port": 5432}, "api-key": "secret", "debug": true}`,
+		"myapp/token": "plain",
+	}
+
+	vars, err := SecretsManager(client, "myapp/prod").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PORT": "5432",
+		"API_KEY": "secret",
+		"DEBUG":   "true",
+	}, vars)
+
+	vars, err = SecretsManager(client, "myapp/token").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"TOKEN": "plain"}, vars)
+}
+
+func TestParameterStore(t *testing.T) {
+	client := &fakeSSM{pages: [][]types.Parameter{
+		{{Name: aws.String("/myapp/prod/db/host"), Value: aws.String("localhost")}},
+		{{Name: aws.String("/myapp/prod/api-key"), Value: aws.String("secret")}},
+	}}
+
+	vars, err := ParameterStore(client, "/myapp/prod/*").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_HOST": "localhost", "API_KEY": "secret"}, vars)
+
+	vars, err = ParameterStore(client, "/myapp/prod/token").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"TOKEN": "single"}, vars)
+}
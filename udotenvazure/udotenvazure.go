@@ -0,0 +1,113 @@
+// Package udotenvazure loads variables from Azure Key Vault. Importing it
+// registers the azkv:// scheme for the env flags: `-e azkv://myvault` loads
+// all the enabled secrets of the vault myvault.vault.azure.net, and
+// `-e azkv://myvault/myapp-` only the ones whose names start with `myapp-`.
+// The host may also be given in full, e.g. azkv://myvault.vault.azure.cn.
+//
+// Requests are authenticated with azidentity.DefaultAzureCredential, which
+// covers environment credentials, workload and managed identities, and the
+// Azure CLI.
+package udotenvazure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// Scheme is the scheme of Key Vault references.
+const Scheme = "azkv"
+
+func init() {
+	udotenv.RegisterScheme(Scheme, func(ref string) (udotenv.Source, error) {
+		vaultURL, prefix := parseRef(ref)
+		return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+			cred, err := azidentity.NewDefaultAzureCredential(nil)
+			if err != nil {
+				return nil, err
+			}
+			client, err := azsecrets.NewClient(vaultURL, cred, nil)
+			if err != nil {
+				return nil, err
+			}
+			return Source(client, Options{Prefix: prefix}).Fetch(ctx)
+		}), nil
+	})
+}
+
+// parseRef splits an azkv:// reference into the URL of the vault and the
+// prefix of the secret names.
+func parseRef(ref string) (vaultURL, prefix string) {
+	host, prefix, _ := strings.Cut(strings.TrimPrefix(ref, Scheme+"://"), "/")
+	if !strings.Contains(host, ".") {
+		host += ".vault.azure.net"
+	}
+	return "https://" + host + "/", prefix
+}
+
+// SecretsAPI is the part of the azsecrets client used by Source.
+type SecretsAPI interface {
+	NewListSecretPropertiesPager(options *azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse]
+	GetSecret(ctx context.Context, name string, version string,
+		options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error)
+}
+
+// Options selects the secrets loaded by Source and names their variables.
+type Options struct {
+	// Prefix, when set, restricts the secrets to the ones whose names start
+	// with it. The prefix is trimmed from the names.
+	Prefix string
+	// KeyFunc converts the name of a secret, without the prefix, to the name
+	// of its variable. It defaults to EnvKey.
+	KeyFunc func(name string) string
+}
+
+// Source returns a source loading the enabled secrets of the vault of client
+// selected by opts.
+func Source(client SecretsAPI, opts Options) udotenv.Source {
+	keyFunc := opts.KeyFunc
+	if keyFunc == nil {
+		keyFunc = EnvKey
+	}
+
+	return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		vars := make(map[string]string)
+		pager := client.NewListSecretPropertiesPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, props := range page.Value {
+				if props.ID == nil || (props.Attributes != nil && props.Attributes.Enabled != nil && !*props.Attributes.Enabled) {
+					continue
+				}
+				name, ok := strings.CutPrefix(props.ID.Name(), opts.Prefix)
+				if !ok {
+					continue
+				}
+
+				secret, err := client.GetSecret(ctx, props.ID.Name(), "", nil)
+				if err != nil {
+					return nil, err
+				}
+				if secret.Value != nil {
+					vars[keyFunc(name)] = *secret.Value
+				}
+			}
+		}
+		return vars, nil
+	})
+}
+
+// EnvKey converts the name of a secret to a variable name. Key Vault names
+// are made of letters, digits and dashes, so `db-password` becomes
+// `DB_PASSWORD`.
+func EnvKey(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
@@ -0,0 +1,70 @@
+package udotenvazure
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVault struct {
+	secrets  map[string]string
+	disabled string
+}
+
+func (f fakeVault) NewListSecretPropertiesPager(options *azsecrets.ListSecretPropertiesOptions) *runtime.Pager[azsecrets.ListSecretPropertiesResponse] {
+	var page azsecrets.ListSecretPropertiesResponse
+	for name := range f.secrets {
+		id := azsecrets.ID("https://myvault.vault.azure.net/secrets/" + name)
+		enabled := name != f.disabled
+		page.Value = append(page.Value, &azsecrets.SecretProperties{
+			ID:         &id,
+			Attributes: &azsecrets.SecretAttributes{Enabled: &enabled},
+		})
+	}
+
+	return runtime.NewPager(runtime.PagingHandler[azsecrets.ListSecretPropertiesResponse]{
+		More: func(azsecrets.ListSecretPropertiesResponse) bool { return false },
+		Fetcher: func(context.Context, *azsecrets.ListSecretPropertiesResponse) (azsecrets.ListSecretPropertiesResponse, error) {
+			return page, nil
+		},
+	})
+}
+
+func (f fakeVault) GetSecret(ctx context.Context, name string, version string,
+	options *azsecrets.GetSecretOptions) (azsecrets.GetSecretResponse, error) {
+	value := f.secrets[name]
+	return azsecrets.GetSecretResponse{Secret: azsecrets.Secret{Value: &value}}, nil
+}
+
+func TestSource(t *testing.T) {
+	client := fakeVault{
+		secrets: map[string]string{
+			"myapp-db-password": "secret",
+			"myapp-old":         "disabled",
+			"other-api-key":     "other",
+		},
+		disabled: "myapp-old",
+	}
+
+	vars, err := Source(client, Options{}).Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"MYAPP_DB_PASSWORD": "secret", "OTHER_API_KEY": "other"}, vars)
+
+	vars, err = Source(client, Options{Prefix: "myapp-", KeyFunc: strings.ToLower}).Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"db-password": "secret"}, vars)
+}
+
+func TestParseRef(t *testing.T) {
+	url, prefix := parseRef("azkv://myvault")
+	assert.Equal(t, "https://myvault.vault.azure.net/", url)
+	assert.Equal(t, "", prefix)
+
+	url, prefix = parseRef("azkv://myvault.vault.azure.cn/myapp-")
+	assert.Equal(t, "https://myvault.vault.azure.cn/", url)
+	assert.Equal(t, "myapp-", prefix)
+}
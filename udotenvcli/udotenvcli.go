@@ -0,0 +1,103 @@
+// Package udotenvcli provides the udotenv flags and a Before hook for
+// urfave/cli v2 applications, so that `-e/--envs` and `--env-overload` load
+// the env files before the actions run:
+//
+//	app := &cli.App{
+//	    Flags:  udotenvcli.CLIFlags(nil),
+//	    Before: udotenvcli.Before(nil),
+//	}
+//	err := app.Run(udotenvcli.Args(os.Args, nil))
+package udotenvcli
+
+import (
+	"flag"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/urfave/cli/v2"
+)
+
+// metadataKey is the key of the UdotEnv in the metadata of the application.
+const metadataKey = "udotenv"
+
+// newUdotEnv returns an UdotEnv for config, registering its flags on a flag
+// set of its own.
+func newUdotEnv(config *udotenv.Config) (*udotenv.UdotEnv, *flag.FlagSet) {
+	fs := flag.NewFlagSet("udotenv", flag.ContinueOnError)
+	return udotenv.NewWithFlagSet(fs, config), fs
+}
+
+// CLIFlags returns the env, overload and dry-run flags of config. If config
+// is nil, the default configuration is used. The first name of each
+// parameter is the name of its flag and the other ones are its aliases, so
+// the default configuration yields `--envs, -e`, `--env-overload, --eo, -o`
+// and `--env-dry-run`.
+func CLIFlags(config *udotenv.Config) []cli.Flag {
+	ue, fs := newUdotEnv(config)
+
+	var flags []cli.Flag
+	if names := ue.Config.EnvFlags; len(names) > 0 {
+		flags = append(flags, &cli.StringSliceFlag{
+			Name:    names[0],
+			Aliases: names[1:],
+			Usage:   fs.Lookup(names[0]).Usage,
+		})
+	}
+	if names := ue.Config.OverloadFlags; len(names) > 0 {
+		flags = append(flags, &cli.BoolFlag{
+			Name:    names[0],
+			Aliases: names[1:],
+			Usage:   fs.Lookup(names[0]).Usage,
+			Value:   ue.Config.OverloadByDefault,
+		})
+	}
+	if names := ue.Config.DryRunFlags; len(names) > 0 {
+		flags = append(flags, &cli.BoolFlag{
+			Name:    names[0],
+			Aliases: names[1:],
+			Usage:   fs.Lookup(names[0]).Usage,
+		})
+	}
+	return flags
+}
+
+// Before returns a Before hook loading the env files passed through the flags
+// of CLIFlags with the same config. The UdotEnv is kept in the metadata of the
+// application, see FromContext.
+func Before(config *udotenv.Config) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		ue, _ := newUdotEnv(config)
+		if names := ue.Config.EnvFlags; len(names) > 0 {
+			ue.EnvParam = c.StringSlice(names[0])
+		}
+		if names := ue.Config.OverloadFlags; len(names) > 0 {
+			ue.OverloadParam = c.Bool(names[0])
+		}
+		if names := ue.Config.DryRunFlags; len(names) > 0 {
+			ue.DryRunParam = c.Bool(names[0])
+		}
+
+		if c.App.Metadata == nil {
+			c.App.Metadata = make(map[string]any)
+		}
+		c.App.Metadata[metadataKey] = ue
+		return ue.Load()
+	}
+}
+
+// FromContext returns the UdotEnv of the Before hook, or nil if the hook did
+// not run.
+func FromContext(c *cli.Context) *udotenv.UdotEnv {
+	ue, _ := c.App.Metadata[metadataKey].(*udotenv.UdotEnv)
+	return ue
+}
+
+// Args returns the command line args, including the program name, passed
+// through PrepareArgs for config, so that `-e` alone loads the default file:
+// urfave/cli requires a value otherwise.
+func Args(args []string, config *udotenv.Config) []string {
+	if len(args) == 0 {
+		return args
+	}
+	ue, _ := newUdotEnv(config)
+	return append([]string{args[0]}, ue.PrepareArgs(args[1:])...)
+}
@@ -0,0 +1,63 @@
+package udotenvcli
+
+import (
+	"os"
+	"testing"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func testConfig() *udotenv.Config {
+	config := udotenv.GetDefaultConfig()
+	config.DefaultEnvPath = ".test.env"
+	config.DefaultsFile = ""
+	return config
+}
+
+func TestCLIFlags(t *testing.T) {
+	flags := CLIFlags(nil)
+
+	assert.Len(t, flags, 3)
+	assert.Equal(t, []string{"envs", "e"}, flags[0].Names())
+	assert.Equal(t, []string{"env-overload", "eo", "o"}, flags[1].Names())
+	assert.Equal(t, []string{"env-dry-run"}, flags[2].Names())
+}
+
+func TestBefore(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("CLI_KEY=file\n"), 0o644)
+	defer os.Remove(".test.env")
+	os.Setenv("CLI_KEY", "env")
+	defer os.Unsetenv("CLI_KEY")
+
+	var got string
+	var ue *udotenv.UdotEnv
+	app := &cli.App{
+		Flags:  CLIFlags(testConfig()),
+		Before: Before(testConfig()),
+		Action: func(c *cli.Context) error {
+			got = os.Getenv("CLI_KEY")
+			ue = FromContext(c)
+			assert.Equal(t, []string{"arg"}, c.Args().Slice())
+			return nil
+		},
+	}
+
+	assert.NoError(t, app.Run(Args([]string{"app", "-e", "-o", "arg"}, testConfig())))
+	assert.Equal(t, "file", got)
+	if assert.NotNil(t, ue) {
+		assert.Equal(t, ".test.env", ue.LoadedKeys()[0].Source)
+	}
+}
+
+func TestBefore_Error(t *testing.T) {
+	app := &cli.App{
+		Flags:  CLIFlags(testConfig()),
+		Before: Before(testConfig()),
+		Action: func(*cli.Context) error { return nil },
+	}
+
+	err := app.Run([]string{"app", "--envs", "missing.env"})
+	assert.ErrorIs(t, err, udotenv.ErrFileNotFound)
+}
@@ -0,0 +1,21 @@
+module github.com/kravlad/go-udotenv/udotenvgcp
+
+go 1.24.1
+
+require (
+	github.com/kravlad/go-udotenv v0.0.0-20261016185143-4da7235d2a7d
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/oauth2 v0.30.0
+)
+
+require (
+	cloud.google.com/go/compute/metadata v0.3.0 // indirect
+	filippo.io/age v1.2.1 // indirect
+	github.com/BurntSushi/toml v1.6.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
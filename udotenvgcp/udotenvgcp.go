@@ -0,0 +1,150 @@
+// Package udotenvgcp loads variables from Google Cloud Secret Manager.
+// Importing it registers the gcp-sm:// scheme for the env flags:
+//
+//   - gcp-sm://projects/P/secrets/S loads the latest version of the secret S
+//     as a single variable named after it, e.g. DB_PASSWORD for db-password.
+//   - gcp-sm://projects/P/secrets/S/versions/V loads the version V.
+//   - gcp-sm://projects/P/secrets/S?format=dotenv parses the payload of the
+//     secret as an env file and loads all its variables.
+//
+// Requests are authenticated with the Application Default Credentials.
+package udotenvgcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"golang.org/x/oauth2/google"
+)
+
+// Scheme is the scheme of Secret Manager references.
+const Scheme = "gcp-sm"
+
+const scope = "https://www.googleapis.com/auth/cloud-platform"
+
+// endpoint is the base URL of the Secret Manager API.
+var endpoint = "https://secretmanager.googleapis.com/v1/"
+
+// Format tells how the payload of a secret is turned into variables.
+type Format int
+
+const (
+	// FormatValue loads the payload as the value of a single variable named
+	// after the secret.
+	FormatValue Format = iota
+	// FormatDotenv parses the payload as an env file.
+	FormatDotenv
+)
+
+func init() {
+	udotenv.RegisterScheme(Scheme, func(ref string) (udotenv.Source, error) {
+		name, format, err := parseRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+			client, err := google.DefaultClient(ctx, scope)
+			if err != nil {
+				return nil, fmt.Errorf("finding default credentials: %w", err)
+			}
+			return Secret(client, name, format).Fetch(ctx)
+		}), nil
+	})
+}
+
+// parseRef splits a gcp-sm:// reference into the name of the secret and the
+// format of its payload.
+func parseRef(ref string) (string, Format, error) {
+	name, query, _ := strings.Cut(strings.TrimPrefix(ref, Scheme+"://"), "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", 0, err
+	}
+
+	switch f := values.Get("format"); f {
+	case "", "value":
+		return name, FormatValue, nil
+	case "dotenv":
+		return name, FormatDotenv, nil
+	default:
+		return "", 0, fmt.Errorf("unknown format %q", f)
+	}
+}
+
+// Secret returns a source loading the secret version at name with client,
+// which must add the credentials to the requests. name is the resource name
+// of the secret, for its latest version, or of a secret version.
+func Secret(client *http.Client, name string, format Format) udotenv.Source {
+	return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		name := strings.Trim(name, "/")
+		if !strings.Contains(name, "/versions/") {
+			name += "/versions/latest"
+		}
+
+		payload, err := access(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if format == FormatDotenv {
+			return udotenv.ParseReader(bytes.NewReader(payload))
+		}
+		secret := path.Base(strings.Split(name, "/versions/")[0])
+		return map[string]string{udotenv.EnvKey(secret): string(payload)}, nil
+	})
+}
+
+// access returns the payload of the secret version at name.
+func access(ctx context.Context, client *http.Client, name string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+name+":access", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Payload struct {
+			Data       string `json:"data"`
+			DataCrc32c string `json:"dataCrc32c"`
+		} `json:"payload"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if body.Error.Message != "" {
+			return nil, fmt.Errorf("%s: %s", resp.Status, body.Error.Message)
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+	if body.Payload.DataCrc32c != "" {
+		sum, err := strconv.ParseUint(body.Payload.DataCrc32c, 10, 32)
+		if err != nil || uint32(sum) != crc32.Checksum(payload, crc32.MakeTable(crc32.Castagnoli)) {
+			return nil, fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,66 @@
+package udotenvgcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newServer(t *testing.T, secrets map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "Secret not found"}})
+			return
+		}
+		sum := crc32.Checksum([]byte(data), crc32.MakeTable(crc32.Castagnoli))
+		_ = json.NewEncoder(w).Encode(map[string]any{"payload": map[string]any{
+			"data":       base64.StdEncoding.EncodeToString([]byte(data)),
+			"dataCrc32c": strconv.FormatUint(uint64(sum), 10),
+		}})
+	}))
+
+	old := endpoint
+	endpoint = server.URL + "/v1/"
+	t.Cleanup(func() {
+		endpoint = old
+		server.Close()
+	})
+	return server
+}
+
+func TestSecret(t *testing.T) {
+	server := newServer(t, map[string]string{
+		"/v1/projects/p/secrets/db-password/versions/latest:access": "secret",
+		"/v1/projects/p/secrets/app/versions/2:access":              "A=1\nB=\"two\"\n",
+	})
+
+	vars, err := Secret(server.Client(), "projects/p/secrets/db-password", FormatValue).Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "secret"}, vars)
+
+	vars, err = Secret(server.Client(), "projects/p/secrets/app/versions/2", FormatDotenv).Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "two"}, vars)
+
+	_, err = Secret(server.Client(), "projects/p/secrets/missing", FormatValue).Fetch(context.Background())
+	assert.EqualError(t, err, "404 Not Found: Secret not found")
+}
+
+func TestParseRef(t *testing.T) {
+	name, format, err := parseRef("gcp-sm://projects/p/secrets/app?format=dotenv")
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/p/secrets/app", name)
+	assert.Equal(t, FormatDotenv, format)
+
+	_, _, err = parseRef("gcp-sm://projects/p/secrets/app?format=yaml")
+	assert.EqualError(t, err, `unknown format "yaml"`)
+}
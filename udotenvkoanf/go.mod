@@ -0,0 +1,24 @@
+module github.com/kravlad/go-udotenv/udotenvkoanf
+
+go 1.24.1
+
+require (
+	github.com/knadh/koanf/maps v0.1.3
+	github.com/knadh/koanf/v2 v2.3.7
+	github.com/kravlad/go-udotenv v0.0.0-20261016185143-4da7235d2a7d
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	filippo.io/age v1.2.1 // indirect
+	github.com/BurntSushi/toml v1.6.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/mitchellh/copystructure v1.2.0 // indirect
+	github.com/mitchellh/reflectwalk v1.0.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,76 @@
+// Package udotenvkoanf provides a koanf provider reading the env files of an
+// UdotEnv, so that applications configured with koanf pick up the files
+// passed through the env flags, with their precedence, expansion and secrets:
+//
+//	ue := udotenv.New(true)
+//	k := koanf.New(".")
+//	err := k.Load(udotenvkoanf.Provider(ue, udotenvkoanf.Opt{Delim: "_"}), nil)
+//
+// The provider reads the files like UdotEnv.Read and does not touch the
+// process environment.
+package udotenvkoanf
+
+import (
+	"github.com/knadh/koanf/maps"
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// Opt holds the options of a provider.
+type Opt struct {
+	// Paths are the env files to read, replacing the files passed through the
+	// env flags if any are given.
+	Paths []string
+	// Delim splits the keys into nested koanf keys, once transformed, e.g.
+	// "." or "_". The keys are kept flat if it is empty.
+	Delim string
+	// Transform, if set, maps every variable to a koanf key and value. The
+	// variables for which it returns an empty key are skipped. The keys are
+	// kept unchanged if it is nil.
+	Transform func(key, value string) (string, any)
+}
+
+// EnvProvider implements koanf.Provider.
+type EnvProvider struct {
+	ue  *udotenv.UdotEnv
+	opt Opt
+}
+
+// Provider returns a koanf provider reading the env files of ue.
+func Provider(ue *udotenv.UdotEnv, opt Opt) *EnvProvider {
+	return &EnvProvider{ue: ue, opt: opt}
+}
+
+// ReadBytes returns the variables in the env format, for koanf's dotenv
+// parser. The keys are not transformed.
+func (p *EnvProvider) ReadBytes() ([]byte, error) {
+	vars, err := p.ue.Read(p.opt.Paths...)
+	if err != nil {
+		return nil, err
+	}
+	return udotenv.Marshal(vars)
+}
+
+// Read returns the variables as a koanf config map.
+func (p *EnvProvider) Read() (map[string]any, error) {
+	vars, err := p.ue.Read(p.opt.Paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := make(map[string]any, len(vars))
+	for k, v := range vars {
+		key, value := k, any(v)
+		if p.opt.Transform != nil {
+			key, value = p.opt.Transform(k, v)
+			if key == "" {
+				continue
+			}
+		}
+		mp[key] = value
+	}
+
+	if p.opt.Delim == "" {
+		return mp, nil
+	}
+	return maps.Unflatten(mp, p.opt.Delim), nil
+}
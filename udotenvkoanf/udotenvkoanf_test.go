@@ -0,0 +1,54 @@
+package udotenvkoanf
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("APP_DB_HOST=localhost\nAPP_DB_PORT=5432\nAPP_URL=http://${APP_DB_HOST}\nOTHER=1\n"), 0o644)
+	defer os.Remove(".test.env")
+
+	ue := udotenv.NewWithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError), &udotenv.Config{DefaultEnvPath: ".test.env"})
+	k := koanf.New(".")
+	err := k.Load(Provider(ue, Opt{
+		Paths: []string{".test.env"},
+		Delim: ".",
+		Transform: func(key, value string) (string, any) {
+			if !strings.HasPrefix(key, "APP_") {
+				return "", nil
+			}
+			return strings.ReplaceAll(strings.ToLower(strings.TrimPrefix(key, "APP_")), "_", "."), value
+		},
+	}), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", k.String("db.host"))
+	assert.Equal(t, 5432, k.Int("db.port"))
+	assert.Equal(t, "http://localhost", k.String("url"))
+	assert.False(t, k.Exists("other"))
+	_, set := os.LookupEnv("APP_DB_HOST")
+	assert.False(t, set)
+}
+
+func TestProvider_ReadBytes(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("KEY=value with spaces\n"), 0o644)
+	defer os.Remove(".test.env")
+
+	b, err := Provider(udotenv.NewWithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError), nil), Opt{Paths: []string{".test.env"}}).ReadBytes()
+	assert.NoError(t, err)
+	vars, err := udotenv.ParseReader(strings.NewReader(string(b)))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"KEY": "value with spaces"}, vars)
+}
+
+func TestProvider_Error(t *testing.T) {
+	_, err := Provider(udotenv.NewWithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError), nil), Opt{Paths: []string{"missing.env"}}).Read()
+	assert.ErrorIs(t, err, udotenv.ErrFileNotFound)
+}
@@ -0,0 +1,77 @@
+// Package udotenvkong provides a kong resolver reading the env files of an
+// UdotEnv, so that the flags of kong structs get their defaults from the
+// files:
+//
+//	var cli struct {
+//	    Port int `env:"PORT"`
+//	}
+//	ue := udotenv.New(false)
+//	parser := kong.Must(&cli, kong.Resolvers(udotenvkong.Resolver(ue, ".env")))
+//
+// The flags set on the command line win over the files.
+package udotenvkong
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/kong"
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// resolver implements kong.Resolver.
+type resolver struct {
+	ue    *udotenv.UdotEnv
+	paths []string
+
+	once sync.Once
+	vars map[string]string
+	err  error
+}
+
+// Resolver returns a kong resolver reading the env files of ue, the ones of
+// paths if any are given, like UdotEnv.Read. The files are read once, when
+// the first flag is resolved.
+//
+// A flag is resolved from the variables of its `env` tag, the first one
+// defined in the files winning, or else from the variable named after the
+// flag, upper-cased with dashes and dots replaced by underscores, so that
+// `--db-host` reads DB_HOST. As with Load, a variable already set in the
+// environment keeps its value unless the overload flags are set.
+func Resolver(ue *udotenv.UdotEnv, paths ...string) kong.Resolver {
+	return &resolver{ue: ue, paths: paths}
+}
+
+func (r *resolver) Validate(*kong.Application) error {
+	return nil
+}
+
+func (r *resolver) Resolve(_ *kong.Context, _ *kong.Path, flag *kong.Flag) (any, error) {
+	r.once.Do(func() {
+		r.vars, r.err = r.ue.Read(r.paths...)
+	})
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	for _, key := range keys(flag) {
+		value, ok := r.vars[key]
+		if !ok {
+			continue
+		}
+		if env, set := os.LookupEnv(key); set && !r.ue.Overload() {
+			return env, nil
+		}
+		return value, nil
+	}
+	return nil, nil
+}
+
+// keys returns the names of the variables of flag.
+func keys(flag *kong.Flag) []string {
+	if len(flag.Tag.Envs) > 0 {
+		return flag.Tag.Envs
+	}
+	return []string{strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(flag.Name))}
+}
@@ -0,0 +1,54 @@
+package udotenvkong
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+type cli struct {
+	DBHost  string        `name:"db-host"`
+	Port    int           `env:"APP_PORT,PORT"`
+	Timeout time.Duration `default:"1s"`
+	Name    string        `env:"KONG_NAME"`
+}
+
+func parse(t *testing.T, ue *udotenv.UdotEnv, args ...string) (*cli, error) {
+	t.Helper()
+	var c cli
+	parser, err := kong.New(&c, kong.Resolvers(Resolver(ue, ".test.env")), kong.Exit(func(int) {}))
+	assert.NoError(t, err)
+	_, err = parser.Parse(args)
+	return &c, err
+}
+
+func TestResolver(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("DB_HOST=db\nDB_URL=postgres://${DB_HOST}\nPORT=8080\nTIMEOUT=5s\nKONG_NAME=file\n"), 0o644)
+	defer os.Remove(".test.env")
+	os.Setenv("KONG_NAME", "env")
+	defer os.Unsetenv("KONG_NAME")
+
+	ue := udotenv.NewWithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	c, err := parse(t, ue, "--timeout=2s")
+	assert.NoError(t, err)
+	assert.Equal(t, &cli{DBHost: "db", Port: 8080, Timeout: 2 * time.Second, Name: "env"}, c)
+	_, set := os.LookupEnv("DB_HOST")
+	assert.False(t, set)
+
+	ue.OverloadParam = true
+	c, err = parse(t, ue)
+	assert.NoError(t, err)
+	assert.Equal(t, "file", c.Name)
+	assert.Equal(t, 5*time.Second, c.Timeout)
+}
+
+func TestResolver_Error(t *testing.T) {
+	ue := udotenv.NewWithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	_, err := parse(t, ue)
+	assert.ErrorIs(t, err, udotenv.ErrFileNotFound)
+}
@@ -0,0 +1,85 @@
+// Package udotenvpflag registers the udotenv flags on a pflag.FlagSet, so that
+// `-e/--envs` and `--env-overload` work inside Cobra commands and other pflag
+// based programs.
+package udotenvpflag
+
+import (
+	"flag"
+	"strings"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/spf13/pflag"
+)
+
+// value adapts a flag.Value to pflag.Value.
+type value struct {
+	flag.Value
+	typ string
+}
+
+func (v value) Type() string {
+	return v.typ
+}
+
+// RegisterPFlags registers the env, overload and dry-run flags of config on
+// fs. If config is nil, the default configuration is used. A single-letter
+// flag name becomes the shorthand of the first long name of the same
+// parameter, so the default configuration yields `-e, --envs`,
+// `-o, --env-overload` and `--env-dry-run`.
+//
+// With Cobra, register the flags as persistent flags and load the files once
+// they are parsed:
+//
+//	udotEnv := udotenvpflag.RegisterPFlags(rootCmd.PersistentFlags(), nil)
+//	rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
+//	    return udotEnv.Load()
+//	}
+//
+// pflag requires a value for `-e`; to let `-e` alone load the default file,
+// pass the arguments through PrepareArgs first:
+//
+//	rootCmd.SetArgs(udotEnv.PrepareArgs(os.Args[1:]))
+func RegisterPFlags(fs *pflag.FlagSet, config *udotenv.Config) *udotenv.UdotEnv {
+	goFlags := flag.NewFlagSet("udotenv", flag.ContinueOnError)
+	ue := udotenv.NewWithFlagSet(goFlags, config)
+
+	register(fs, goFlags, ue.Config.EnvFlags, "stringSlice", "")
+	register(fs, goFlags, ue.Config.OverloadFlags, "bool", "true")
+	register(fs, goFlags, ue.Config.DryRunFlags, "bool", "true")
+	return ue
+}
+
+// register adds the flags of goFlags called names to fs.
+func register(fs *pflag.FlagSet, goFlags *flag.FlagSet, names []string, typ, noOptDefVal string) {
+	var long, short []string
+	for _, name := range names {
+		if len(name) == 1 {
+			short = append(short, name)
+		} else {
+			long = append(long, name)
+		}
+	}
+
+	for i, name := range long {
+		shorthand := ""
+		if i == 0 && len(short) > 0 {
+			shorthand, short = short[0], short[1:]
+		}
+
+		f := goFlags.Lookup(name)
+		pf := fs.VarPF(value{f.Value, typ}, name, shorthand, usage(f))
+		pf.NoOptDefVal = noOptDefVal
+	}
+
+	for _, name := range short {
+		f := goFlags.Lookup(name)
+		pf := fs.VarPF(value{f.Value, typ}, name, name, usage(f))
+		pf.NoOptDefVal = noOptDefVal
+	}
+}
+
+// usage returns the usage message of f, with the aliases referring to the
+// double-dash form of pflag.
+func usage(f *flag.Flag) string {
+	return strings.Replace(f.Usage, "alias for -", "alias for --", 1)
+}
@@ -0,0 +1,41 @@
+package udotenvpflag
+
+import (
+	"os"
+	"testing"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterPFlags(fs, nil)
+
+	assert.Equal(t, "e", fs.Lookup("envs").Shorthand)
+	assert.Equal(t, "o", fs.Lookup("env-overload").Shorthand)
+	assert.NotNil(t, fs.Lookup("eo"))
+	assert.Equal(t, "true", fs.Lookup("eo").NoOptDefVal)
+	assert.Equal(t, "true", fs.Lookup("env-dry-run").NoOptDefVal)
+	assert.Equal(t, "alias for --env-overload", fs.Lookup("eo").Usage)
+}
+
+func TestRegisterPFlags_Load(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("PFLAG_KEY=file\n"), 0o644)
+	defer os.Remove(".test.env")
+	os.Setenv("PFLAG_KEY", "env")
+	defer os.Unsetenv("PFLAG_KEY")
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	udotEnv := RegisterPFlags(fs, &udotenv.Config{
+		EnvFlags:       []string{"envs", "e"},
+		OverloadFlags:  []string{"env-overload", "o"},
+		DefaultEnvPath: ".test.env",
+	})
+
+	assert.NoError(t, fs.Parse(udotEnv.PrepareArgs([]string{"-e", "--env-overload", "arg"})))
+	assert.Equal(t, []string{"arg"}, fs.Args())
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "file", os.Getenv("PFLAG_KEY"))
+}
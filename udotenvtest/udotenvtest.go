@@ -0,0 +1,96 @@
+// Package udotenvtest provides helpers for tests that depend on environment
+// variables. It lives in a separate package so that the main package does not
+// import `testing`.
+package udotenvtest
+
+import (
+	"os"
+	"testing"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// sentinelKey is set by LoadForTest through t.Setenv, only for its check
+// against parallel tests.
+const sentinelKey = "UDOTENVTEST_LOAD_FOR_TEST"
+
+// WithEnv applies vars to the process environment and returns a function that
+// restores the previous state. Keys that were not set before are unset again,
+// keys that were set get their previous values back.
+//
+// Example:
+//
+//	func TestSomething(t *testing.T) {
+//	    defer udotenvtest.WithEnv(t, map[string]string{"PORT": "8080"})()
+//	    ...
+//	}
+func WithEnv(t testing.TB, vars map[string]string) func() {
+	t.Helper()
+
+	type previous struct {
+		value string
+		set   bool
+	}
+
+	prev := make(map[string]previous, len(vars))
+	restore := func() {
+		for k, p := range prev {
+			if p.set {
+				os.Setenv(k, p.value)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}
+
+	for k, v := range vars {
+		value, set := os.LookupEnv(k)
+		prev[k] = previous{value: value, set: set}
+
+		if err := os.Setenv(k, v); err != nil {
+			restore()
+			t.Fatalf("setting %s: %v", k, err)
+		}
+	}
+	return restore
+}
+
+// LoadForTest loads the env files at paths, .env if none is given, with
+// overload, and restores the whole environment when the test and its subtests
+// complete, unsetting the variables that did not exist before. A file that
+// cannot be loaded fails the test. The returned instance gives access to the
+// typed getters. Only the given files are loaded, without the
+// `.env.defaults` file of the default config.
+//
+// Like t.Setenv, it changes the environment of the whole process, so it must
+// not be used in parallel tests: it panics, as t.Setenv does, if the test or
+// one of its ancestors called t.Parallel.
+//
+// Example:
+//
+//	func TestServer(t *testing.T) {
+//	    udotenvtest.LoadForTest(t, "testdata/test.env")
+//	    ...
+//	}
+func LoadForTest(t testing.TB, paths ...string) *udotenv.UdotEnv {
+	t.Helper()
+	t.Setenv(sentinelKey, "1")
+
+	if len(paths) == 0 {
+		paths = []string{".env"}
+	}
+	config := udotenv.GetDefaultConfig()
+	config.DefaultsFile = ""
+	ue := &udotenv.UdotEnv{Config: config, EnvParam: paths, OverloadParam: true}
+
+	snap := udotenv.Snapshot()
+	t.Cleanup(func() {
+		if err := snap.Restore(); err != nil {
+			t.Errorf("restoring the environment: %v", err)
+		}
+	})
+	if err := ue.Load(); err != nil {
+		t.Fatalf("loading env files: %v", err)
+	}
+	return ue
+}
@@ -0,0 +1,86 @@
+package udotenvtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEnv(t *testing.T) {
+	os.Setenv("UDOTENVTEST_SET", "old")
+	defer os.Unsetenv("UDOTENVTEST_SET")
+	os.Unsetenv("UDOTENVTEST_UNSET")
+
+	cleanup := WithEnv(t, map[string]string{
+		"UDOTENVTEST_SET":   "new",
+		"UDOTENVTEST_UNSET": "value",
+	})
+
+	assert.Equal(t, "new", os.Getenv("UDOTENVTEST_SET"))
+	assert.Equal(t, "value", os.Getenv("UDOTENVTEST_UNSET"))
+
+	cleanup()
+
+	assert.Equal(t, "old", os.Getenv("UDOTENVTEST_SET"))
+	_, ok := os.LookupEnv("UDOTENVTEST_UNSET")
+	assert.False(t, ok)
+}
+
+func TestLoadForTest(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_ = os.WriteFile(".env.defaults", []byte("UDOTENVTEST_DEFAULT=defaults\n"), 0o644)
+	path := filepath.Join(t.TempDir(), "test.env")
+	_ = os.WriteFile(path, []byte("UDOTENVTEST_LOADED=file\nUDOTENVTEST_OVER=file\n"), 0o644)
+	os.Setenv("UDOTENVTEST_OVER", "env")
+	defer os.Unsetenv("UDOTENVTEST_OVER")
+
+	t.Run("load", func(t *testing.T) {
+		ue := LoadForTest(t, path)
+		assert.Equal(t, "file", os.Getenv("UDOTENVTEST_LOADED"))
+		assert.Equal(t, "file", os.Getenv("UDOTENVTEST_OVER"))
+		_, ok := os.LookupEnv("UDOTENVTEST_DEFAULT")
+		assert.False(t, ok)
+
+		v, err := ue.GetString("UDOTENVTEST_LOADED")
+		assert.NoError(t, err)
+		assert.Equal(t, "file", v)
+	})
+
+	_, ok := os.LookupEnv("UDOTENVTEST_LOADED")
+	assert.False(t, ok)
+	assert.Equal(t, "env", os.Getenv("UDOTENVTEST_OVER"))
+}
+
+func TestLoadForTest_Parallel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.env")
+	_ = os.WriteFile(path, []byte("UDOTENVTEST_PARALLEL=file\n"), 0o644)
+
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { LoadForTest(t, path) })
+		_, ok := os.LookupEnv("UDOTENVTEST_PARALLEL")
+		assert.False(t, ok)
+	})
+}
+
+func TestLoadForTest_Error(t *testing.T) {
+	ft := &fatalTB{TB: t}
+	func() {
+		defer func() { _ = recover() }()
+		LoadForTest(ft, filepath.Join(t.TempDir(), "missing.env"))
+	}()
+	assert.True(t, ft.failed)
+}
+
+// fatalTB records the call to Fatalf instead of stopping the test.
+type fatalTB struct {
+	testing.TB
+	failed bool
+}
+
+func (t *fatalTB) Fatalf(format string, args ...any) {
+	t.failed = true
+	panic("fatal")
+}
@@ -0,0 +1,268 @@
+// Package udotenvvault loads variables from the KV secrets engine of
+// HashiCorp Vault. Importing it registers the vault:// scheme for the env
+// flags, so that `-e vault://secret/data/myapp` loads the fields of the secret
+// at secret/data/myapp. Both versions of the KV engine are supported.
+//
+// The scheme uses a client configured from the environment, see NewFromEnv.
+// Applications that need another configuration or lease renewal create a
+// Client and add its Source to `Config.Sources`.
+package udotenvvault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	udotenv "github.com/kravlad/go-udotenv"
+)
+
+// Scheme is the scheme of Vault references.
+const Scheme = "vault"
+
+func init() {
+	udotenv.RegisterScheme(Scheme, func(ref string) (udotenv.Source, error) {
+		return NewFromEnv().Source(strings.TrimPrefix(ref, Scheme+"://")), nil
+	})
+}
+
+// Client reads secrets from Vault. It authenticates with Token or, if Token
+// is empty, logs in with the AppRole RoleID and SecretID.
+type Client struct {
+	// Addr is the address of the Vault server, e.g. https://vault:8200.
+	Addr string
+	// Token is the Vault token.
+	Token string
+	// RoleID and SecretID are the AppRole credentials used when Token is
+	// empty.
+	RoleID   string
+	SecretID string
+	// Namespace is the Vault Enterprise namespace, if any.
+	Namespace string
+	// HTTPClient is the client used for the requests. It defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	ttl       time.Duration
+	renewable bool
+}
+
+// NewFromEnv returns a client configured with the variables used by the
+// Vault CLI: VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE, plus VAULT_ROLE_ID
+// and VAULT_SECRET_ID for AppRole.
+func NewFromEnv() *Client {
+	return &Client{
+		Addr:      os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		RoleID:    os.Getenv("VAULT_ROLE_ID"),
+		SecretID:  os.Getenv("VAULT_SECRET_ID"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+}
+
+// Source returns a source loading the fields of the secret at path, e.g.
+// "secret/data/myapp" for a KV v2 engine mounted at secret/. String fields
+// are loaded as is, other fields are JSON-encoded.
+func (c *Client) Source(path string) udotenv.Source {
+	return udotenv.SourceFunc(func(ctx context.Context) (map[string]string, error) {
+		return c.read(ctx, path)
+	})
+}
+
+// Renew renews the lease of the token of the client until ctx is done,
+// about when two thirds of its TTL have elapsed, and returns the error of
+// the first renewal that fails, or ctx.Err(). Tokens that are not renewable
+// are not renewed.
+func (c *Client) Renew(ctx context.Context) error {
+	token, err := c.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.Token != "" {
+		// the TTL of a static token is only known by looking it up
+		var resp response
+		if err := c.do(ctx, http.MethodGet, "auth/token/lookup-self", token, nil, &resp); err != nil {
+			return fmt.Errorf("looking up token: %w", err)
+		}
+		ttl, _ := resp.Data["ttl"].(float64)
+		renewable, _ := resp.Data["renewable"].(bool)
+		c.setAuth(&auth{LeaseDuration: int(ttl), Renewable: renewable})
+	}
+
+	for {
+		c.mu.Lock()
+		token, ttl, renewable := c.token, c.ttl, c.renewable
+		c.mu.Unlock()
+		if !renewable || ttl <= 0 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ttl * 2 / 3):
+		}
+
+		var resp response
+		if err := c.do(ctx, http.MethodPost, "auth/token/renew-self", token, nil, &resp); err != nil {
+			return fmt.Errorf("renewing token: %w", err)
+		}
+		c.setAuth(resp.Auth)
+	}
+}
+
+// response is the envelope of the responses of the Vault API.
+type response struct {
+	Data   map[string]any `json:"data"`
+	Auth   *auth          `json:"auth"`
+	Errors []string       `json:"errors"`
+}
+
+type auth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// read reads the fields of the secret at path.
+func (c *Client) read(ctx context.Context, path string) (map[string]string, error) {
+	token, err := c.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp response
+	if err := c.do(ctx, http.MethodGet, path, token, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	data := resp.Data
+	// KV v2 nests the fields of the secret along with its metadata
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	vars := make(map[string]string, len(data))
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			vars[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		vars[k] = string(b)
+	}
+	return vars, nil
+}
+
+// authenticate returns the token of the client, logging in with AppRole
+// first if needed.
+func (c *Client) authenticate(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" {
+		return c.token, nil
+	}
+	if c.Token != "" {
+		c.token = c.Token
+		return c.token, nil
+	}
+	if c.RoleID == "" {
+		return "", errors.New("no Vault token or AppRole credentials")
+	}
+
+	body := map[string]string{"role_id": c.RoleID, "secret_id": c.SecretID}
+	var resp response
+	if err := c.do(ctx, http.MethodPost, "auth/approle/login", "", body, &resp); err != nil {
+		return "", fmt.Errorf("logging in with AppRole: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", errors.New("logging in with AppRole: no token returned")
+	}
+	c.token = resp.Auth.ClientToken
+	c.ttl = time.Duration(resp.Auth.LeaseDuration) * time.Second
+	c.renewable = resp.Auth.Renewable
+	return c.token, nil
+}
+
+func (c *Client) setAuth(a *auth) {
+	if a == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if a.ClientToken != "" {
+		c.token = a.ClientToken
+	}
+	c.ttl = time.Duration(a.LeaseDuration) * time.Second
+	c.renewable = a.Renewable
+}
+
+// do sends a request authenticated with token to the Vault API and decodes
+// the response into out.
+func (c *Client) do(ctx context.Context, method, path, token string, body any, out *response) error {
+	if c.Addr == "" {
+		return errors.New("no Vault address, set VAULT_ADDR")
+	}
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	url := strings.TrimRight(c.Addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.Namespace)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		// the body of an error, possibly served by a proxy, is only used
+		// for the messages of Vault
+		var failure response
+		if json.NewDecoder(resp.Body).Decode(&failure) == nil && len(failure.Errors) > 0 {
+			return fmt.Errorf("%s: %s", resp.Status, strings.Join(failure.Errors, "; "))
+		}
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decoding response of %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package udotenvvault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write := func(v any) { _ = json.NewEncoder(w).Encode(v) }
+
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				write(map[string]any{"errors": []string{"invalid role or secret ID"}})
+				return
+			}
+			write(map[string]any{"auth": map[string]any{"client_token": "approle-token", "lease_duration": 3, "renewable": true}})
+			return
+		case "/v1/auth/token/renew-self":
+			write(map[string]any{"auth": map[string]any{"client_token": r.Header.Get("X-Vault-Token"), "lease_duration": 3, "renewable": true}})
+			return
+		}
+
+		if tok := r.Header.Get("X-Vault-Token"); tok != "token" && tok != "approle-token" {
+			w.WriteHeader(http.StatusForbidden)
+			write(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/myapp":
+			write(map[string]any{"data": map[string]any{
+				"data":     map[string]any{"DB_HOST": "localhost", "DB_PORT": 5432},
+				"metadata": map[string]any{"version": 1},
+			}})
+		case "/v1/kv/myapp":
+			write(map[string]any{"data": map[string]any{"API_KEY": "secret"}})
+		case "/v1/kv/proxied":
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("<html>Bad Gateway</html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			write(map[string]any{"errors": []string{}})
+		}
+	}))
+}
+
+func TestSource(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	client := &Client{Addr: server.URL, Token: "token"}
+	vars, err := client.Source("secret/data/myapp").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_HOST": "localhost", "DB_PORT": "5432"}, vars)
+
+	vars, err = client.Source("kv/myapp").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_KEY": "secret"}, vars)
+
+	_, err = client.Source("secret/data/missing").Fetch(context.Background())
+	assert.EqualError(t, err, "unexpected status 404 Not Found")
+
+	// the status is reported rather than the body that is not JSON
+	_, err = client.Source("kv/proxied").Fetch(context.Background())
+	assert.EqualError(t, err, "unexpected status 502 Bad Gateway")
+
+	_, err = (&Client{Addr: server.URL, Token: "wrong"}).Source("kv/myapp").Fetch(context.Background())
+	assert.EqualError(t, err, "403 Forbidden: permission denied")
+}
+
+func TestSource_AppRole(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	client := &Client{Addr: server.URL, RoleID: "role", SecretID: "secret"}
+	vars, err := client.Source("kv/myapp").Fetch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_KEY": "secret"}, vars)
+
+	_, err = (&Client{Addr: server.URL, RoleID: "role"}).Source("kv/myapp").Fetch(context.Background())
+	assert.EqualError(t, err, "logging in with AppRole: 400 Bad Request: invalid role or secret ID")
+}
+
+func TestRenew(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	client := &Client{Addr: server.URL, RoleID: "role", SecretID: "secret"}
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.Renew(ctx), context.DeadlineExceeded)
+	assert.Equal(t, "approle-token", client.token)
+}
+
+func TestScheme(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "token")
+	defer os.Unsetenv("DB_HOST")
+	defer os.Unsetenv("DB_PORT")
+
+	udotEnv := &udotenv.UdotEnv{Config: udotenv.GetDefaultConfig(), EnvParam: []string{"vault://secret/data/myapp"}}
+	assert.NoError(t, udotEnv.Load())
+	assert.Equal(t, "localhost", os.Getenv("DB_HOST"))
+}
@@ -0,0 +1,122 @@
+// Package udotenvviper feeds Viper with the env files of an UdotEnv, so that
+// applications configured with Viper pick up the files passed through the env
+// flags, with their precedence, expansion and secrets.
+//
+// Merge merges the variables into a Viper instance:
+//
+//	ue := udotenv.New(true)
+//	v := viper.New()
+//	err := udotenvviper.Merge(v, ue)
+//
+// Register makes the files available as a Viper remote provider named
+// "udotenv" instead, the path of the provider listing the files separated by
+// commas, or empty for the files of the env flags:
+//
+//	udotenvviper.Register(ue)
+//	v.AddRemoteProvider("udotenv", "local", "")
+//	v.SetConfigType("json")
+//	err := v.ReadRemoteConfig()
+//
+// The files are read like UdotEnv.Read and the process environment is left
+// untouched. Viper lower-cases the keys.
+package udotenvviper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/spf13/viper"
+)
+
+// ProviderName is the name of the remote provider installed by Register.
+const ProviderName = "udotenv"
+
+// Merge reads the env files of ue, the ones of paths if any are given, and
+// merges their variables into v.
+func Merge(v *viper.Viper, ue *udotenv.UdotEnv, paths ...string) error {
+	vars, err := ue.Read(paths...)
+	if err != nil {
+		return err
+	}
+
+	cfg := make(map[string]any, len(vars))
+	for k, value := range vars {
+		cfg[k] = value
+	}
+	return v.MergeConfigMap(cfg)
+}
+
+// Register installs the "udotenv" remote provider, reading the env files of
+// ue. The remote config set before, such as the one of
+// github.com/spf13/viper/remote, keeps serving the other providers, so
+// Register must be called after importing it. The provider returns the
+// variables as JSON, the config type of Viper must be "json".
+func Register(ue *udotenv.UdotEnv) {
+	if !slices.Contains(viper.SupportedRemoteProviders, ProviderName) {
+		viper.SupportedRemoteProviders = append(viper.SupportedRemoteProviders, ProviderName)
+	}
+	viper.RemoteConfig = &remoteConfig{ue: ue, next: viper.RemoteConfig}
+}
+
+// remoteFactory is the interface of viper.RemoteConfig.
+type remoteFactory interface {
+	Get(rp viper.RemoteProvider) (io.Reader, error)
+	Watch(rp viper.RemoteProvider) (io.Reader, error)
+	WatchChannel(rp viper.RemoteProvider) (<-chan *viper.RemoteResponse, chan bool)
+}
+
+// remoteConfig serves the "udotenv" provider and delegates the other ones to
+// next.
+type remoteConfig struct {
+	ue   *udotenv.UdotEnv
+	next remoteFactory
+}
+
+func (rc *remoteConfig) Get(rp viper.RemoteProvider) (io.Reader, error) {
+	if rp.Provider() != ProviderName {
+		if rc.next == nil {
+			return nil, viper.UnsupportedRemoteProviderError(rp.Provider())
+		}
+		return rc.next.Get(rp)
+	}
+
+	b, err := rc.read(rp.Path())
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// Watch reads the files again, Viper calling it to poll for changes.
+func (rc *remoteConfig) Watch(rp viper.RemoteProvider) (io.Reader, error) {
+	if rp.Provider() != ProviderName && rc.next != nil {
+		return rc.next.Watch(rp)
+	}
+	return rc.Get(rp)
+}
+
+// WatchChannel is not supported by the "udotenv" provider: its channel never
+// receives, use Watch or UdotEnv.Watch to follow the changes.
+func (rc *remoteConfig) WatchChannel(rp viper.RemoteProvider) (<-chan *viper.RemoteResponse, chan bool) {
+	if rp.Provider() != ProviderName && rc.next != nil {
+		return rc.next.WatchChannel(rp)
+	}
+	return make(chan *viper.RemoteResponse), make(chan bool)
+}
+
+// read returns the variables of the files listed in path as JSON.
+func (rc *remoteConfig) read(path string) ([]byte, error) {
+	var paths []string
+	if path != "" {
+		paths = strings.Split(path, ",")
+	}
+	vars, err := rc.ue.Read(paths...)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(vars)
+}
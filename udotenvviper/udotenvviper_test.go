@@ -0,0 +1,62 @@
+package udotenvviper
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	udotenv "github.com/kravlad/go-udotenv"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUdotEnv(fs *flag.FlagSet) *udotenv.UdotEnv {
+	config := udotenv.GetDefaultConfig()
+	config.DefaultEnvPath = ".test.env"
+	return udotenv.NewWithFlagSet(fs, config)
+}
+
+func TestMerge(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("DB_HOST=localhost\nDB_URL=postgres://${DB_HOST}\n"), 0o644)
+	defer os.Remove(".test.env")
+
+	v := viper.New()
+	v.Set("other", "kept")
+	assert.NoError(t, Merge(v, newUdotEnv(flag.NewFlagSet("test", flag.ContinueOnError)), ".test.env"))
+	assert.Equal(t, "localhost", v.GetString("db_host"))
+	assert.Equal(t, "postgres://localhost", v.GetString("db_url"))
+	assert.Equal(t, "kept", v.GetString("other"))
+	_, set := os.LookupEnv("DB_HOST")
+	assert.False(t, set)
+}
+
+func TestMerge_Error(t *testing.T) {
+	err := Merge(viper.New(), newUdotEnv(flag.NewFlagSet("test", flag.ContinueOnError)), "missing.env")
+	assert.ErrorIs(t, err, udotenv.ErrFileNotFound)
+}
+
+func TestRegister(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("PORT=8080\n"), 0o644)
+	defer os.Remove(".test.env")
+	_ = os.WriteFile(".other.env", []byte("PORT=9090\nNAME=app\n"), 0o644)
+	defer os.Remove(".other.env")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	ue := newUdotEnv(fs)
+	assert.NoError(t, fs.Parse(ue.PrepareArgs([]string{"-e"})))
+	Register(ue)
+
+	v := viper.New()
+	assert.NoError(t, v.AddRemoteProvider(ProviderName, "local", ".test.env,.other.env"))
+	v.SetConfigType("json")
+	assert.NoError(t, v.ReadRemoteConfig())
+	assert.Equal(t, 8080, v.GetInt("port"))
+	assert.Equal(t, "app", v.GetString("name"))
+
+	v = viper.New()
+	assert.NoError(t, v.AddRemoteProvider(ProviderName, "local", ""))
+	v.SetConfigType("json")
+	assert.NoError(t, v.WatchRemoteConfig())
+	assert.Equal(t, "8080", v.GetString("port"))
+	assert.Nil(t, v.Get("name"))
+}
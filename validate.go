@@ -0,0 +1,83 @@
+package udotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// isSet reports whether key is present in the environment with a non-empty
+// value.
+func isSet(key string) bool {
+	return os.Getenv(key) != ""
+}
+
+// missingKeys returns the keys that will not have a non-empty value once
+// pending is applied to the environment.
+func missingKeys(keys []string, pending map[string]string) []string {
+	var missing []string
+	for _, key := range keys {
+		v, ok := pending[key]
+		if !ok {
+			v = os.Getenv(key)
+		}
+		if v == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+func missingKeysError(missing []string) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	return withSentinel(fmt.Errorf("missing required keys: %s", strings.Join(missing, ", ")), ErrValidation)
+}
+
+// checkRequired verifies that the `RequiredKeys` of the config will be set
+// once pending is applied.
+func (ue *UdotEnv) checkRequired(pending map[string]string) error {
+	if ue.Config == nil {
+		return nil
+	}
+	return missingKeysError(missingKeys(ue.Config.RequiredKeys, pending))
+}
+
+// Require checks that every key is set in the environment with a non-empty
+// value. The returned error lists all the missing keys at once.
+func (ue *UdotEnv) Require(keys ...string) error {
+	return missingKeysError(missingKeys(keys, nil))
+}
+
+// RequireExactlyOne checks that exactly one key of every group is set in the
+// environment. A key counts as set when it has a non-empty value. It is meant
+// for mutually exclusive settings, e.g. a DSN or its discrete parts:
+//
+//	err := ue.RequireExactlyOne(
+//	    []string{"DATABASE_URL", "DB_HOST"},
+//	)
+//
+// The returned error describes every group that failed the check.
+func (ue *UdotEnv) RequireExactlyOne(groups ...[]string) error {
+	var errs []error
+	for _, group := range groups {
+		var set []string
+		for _, key := range group {
+			if isSet(key) {
+				set = append(set, key)
+			}
+		}
+
+		switch len(set) {
+		case 0:
+			errs = append(errs, fmt.Errorf("none of %s is set", strings.Join(group, ", ")))
+		case 1:
+		default:
+			errs = append(errs, fmt.Errorf("only one of %s must be set, got %s",
+				strings.Join(group, ", "), strings.Join(set, ", ")))
+		}
+	}
+	return withSentinel(errors.Join(errs...), ErrValidation)
+}
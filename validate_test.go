@@ -0,0 +1,78 @@
+package udotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireExactlyOne(t *testing.T) {
+	os.Setenv("ONE_URL", "postgres://db")
+	defer os.Unsetenv("ONE_URL")
+
+	udotEnv := &UdotEnv{}
+
+	assert.NoError(t, udotEnv.RequireExactlyOne([]string{"ONE_URL", "ONE_HOST"}))
+}
+
+func TestRequireExactlyOne_None(t *testing.T) {
+	udotEnv := &UdotEnv{}
+
+	err := udotEnv.RequireExactlyOne([]string{"NONE_URL", "NONE_HOST"})
+	assert.EqualError(t, err, "none of NONE_URL, NONE_HOST is set")
+}
+
+func TestRequireExactlyOne_Conflict(t *testing.T) {
+	os.Setenv("BOTH_URL", "postgres://db")
+	defer os.Unsetenv("BOTH_URL")
+	os.Setenv("BOTH_HOST", "db")
+	defer os.Unsetenv("BOTH_HOST")
+	os.Setenv("BOTH_PORT", "")
+	defer os.Unsetenv("BOTH_PORT")
+
+	udotEnv := &UdotEnv{}
+
+	err := udotEnv.RequireExactlyOne(
+		[]string{"BOTH_URL", "BOTH_HOST", "BOTH_PORT"},
+		[]string{"BOTH_MISSING"},
+	)
+	assert.EqualError(t, err, "only one of BOTH_URL, BOTH_HOST, BOTH_PORT must be set, got BOTH_URL, BOTH_HOST\n"+
+		"none of BOTH_MISSING is set")
+}
+
+func TestRequire(t *testing.T) {
+	os.Setenv("REQUIRE_SET", "1")
+	defer os.Unsetenv("REQUIRE_SET")
+	os.Setenv("REQUIRE_EMPTY", "")
+	defer os.Unsetenv("REQUIRE_EMPTY")
+
+	udotEnv := &UdotEnv{}
+
+	assert.NoError(t, udotEnv.Require("REQUIRE_SET"))
+	assert.EqualError(t, udotEnv.Require("REQUIRE_SET", "REQUIRE_EMPTY", "REQUIRE_MISSING"),
+		"missing required keys: REQUIRE_EMPTY, REQUIRE_MISSING")
+}
+
+func TestLoad_RequiredKeys(t *testing.T) {
+	_ = os.WriteFile(".test.env", []byte("REQUIRED_A=1\nREQUIRED_C=3\n"), 0o644)
+	defer os.Remove(".test.env")
+
+	udotEnv := &UdotEnv{
+		Config:   &Config{RequiredKeys: []string{"REQUIRED_A", "REQUIRED_B", "REQUIRED_C", "REQUIRED_D"}},
+		EnvParam: stringSlice{".test.env"},
+	}
+
+	assert.EqualError(t, udotEnv.Load(), "missing required keys: REQUIRED_B, REQUIRED_D")
+	_, ok := os.LookupEnv("REQUIRED_A")
+	assert.False(t, ok)
+
+	os.Setenv("REQUIRED_B", "2")
+	defer os.Unsetenv("REQUIRED_B")
+	os.Setenv("REQUIRED_D", "4")
+	defer os.Unsetenv("REQUIRED_D")
+	defer os.Unsetenv("REQUIRED_A")
+	defer os.Unsetenv("REQUIRED_C")
+
+	assert.NoError(t, udotEnv.Load())
+}
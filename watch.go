@@ -0,0 +1,127 @@
+package udotenv
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reload loads the files again with ctx if they changed since the last load.
+func (ue *UdotEnv) reload(ctx context.Context) (map[string]Change, error) {
+	ue.mu.Lock()
+	defer ue.mu.Unlock()
+	ue.ctx = ctx
+	defer func() { ue.ctx = nil }()
+
+	changed, err := ue.changed()
+	if err != nil || !changed {
+		return nil, err
+	}
+	return ue.load()
+}
+
+// Watch watches the env files loaded by the last call to Load and loads them
+// again when they change, applying the same rules as Load. After every load
+// that changed some values, onChange is called with the changes, keyed by
+// variable name.
+//
+// The directories of the files are watched rather than the files themselves,
+// so that files replaced by a rename or a symlink swap, like rotated secrets
+// mounted in a container, are picked up. They are updated after each load,
+// so that the files included since then are watched as well. A load that
+// fails leaves the environment untouched, unless `ContinueOnError` is set,
+// and is retried on the next change. Its error, like the one of a file that
+// cannot be checked for changes or of the watcher itself, is logged and
+// passed to `Config.OnReload`, which is also called after the loads that
+// changed some values; Watch keeps watching.
+//
+// Watch blocks until ctx is done and returns its error.
+func (ue *UdotEnv) Watch(ctx context.Context, onChange func(changed map[string]Change)) error {
+	dirs := ue.watchedDirs()
+	if len(dirs) == 0 {
+		return errors.New("no files to watch, call Load first")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			changes, err := ue.reload(ctx)
+			var watchErr error
+			dirs, watchErr = ue.rewatch(watcher, dirs)
+			ue.reportReload(changes, errors.Join(err, watchErr))
+			// with ContinueOnError, a load may apply changes and fail
+			if len(changes) > 0 && onChange != nil {
+				onChange(changes)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			ue.reportReload(nil, err)
+		}
+	}
+}
+
+// watchedDirs returns the directories of the files stamped by the last load.
+func (ue *UdotEnv) watchedDirs() map[string]bool {
+	ue.mu.RLock()
+	defer ue.mu.RUnlock()
+	dirs := make(map[string]bool, len(ue.stamps))
+	for path := range ue.stamps {
+		dirs[filepath.Dir(path)] = true
+	}
+	return dirs
+}
+
+// rewatch makes watcher watch the directories of the files of the last load
+// instead of dirs, which it watches so far, and returns them.
+func (ue *UdotEnv) rewatch(watcher *fsnotify.Watcher, dirs map[string]bool) (map[string]bool, error) {
+	current := ue.watchedDirs()
+	var errs []error
+	for dir := range current {
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			errs = append(errs, err)
+			delete(current, dir)
+		}
+	}
+	for dir := range dirs {
+		if !current[dir] {
+			_ = watcher.Remove(dir)
+		}
+	}
+	return current, errors.Join(errs...)
+}
+
+// reportReload logs err and passes it to `Config.OnReload`, along with
+// changes, if either is set.
+func (ue *UdotEnv) reportReload(changes map[string]Change, err error) {
+	if err != nil {
+		ue.logger().Warn("reload failed", "err", err)
+	}
+	if (err != nil || len(changes) > 0) && ue.Config != nil && ue.Config.OnReload != nil {
+		ue.Config.OnReload(changes, err)
+	}
+}
@@ -0,0 +1,151 @@
+package udotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("RELOAD_A=1\nRELOAD_B=1\nRELOAD_C=file\n"), 0o644)
+	for _, k := range []string{"RELOAD_A", "RELOAD_B", "RELOAD_C"} {
+		defer os.Unsetenv(k)
+	}
+	os.Setenv("RELOAD_C", "env")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	_ = os.WriteFile(path, []byte("RELOAD_A=2\nRELOAD_C=changed\n"), 0o644)
+	assert.NoError(t, udotEnv.Load())
+
+	assert.Equal(t, "2", os.Getenv("RELOAD_A"))
+	_, ok := os.LookupEnv("RELOAD_B")
+	assert.False(t, ok)
+	assert.Equal(t, "env", os.Getenv("RELOAD_C"))
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("WATCH_A=1\n"), 0o644)
+	defer os.Unsetenv("WATCH_A")
+	defer os.Unsetenv("WATCH_B")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan map[string]Change, 1)
+	done := make(chan error)
+	go func() {
+		done <- udotEnv.Watch(ctx, func(c map[string]Change) { changed <- c })
+	}()
+
+	// give the watcher time to start before changing the file
+	time.Sleep(100 * time.Millisecond)
+	// replaced atomically so that the watcher never sees a partial file
+	_ = os.WriteFile(path+".tmp", []byte("WATCH_A=2\nWATCH_B=1\n"), 0o644)
+	_ = os.Rename(path+".tmp", path)
+
+	select {
+	case c := <-changed:
+		assert.Equal(t, map[string]Change{
+			"WATCH_A": {Kind: Modified, Old: "1", New: "2"},
+			"WATCH_B": {Kind: Added, New: "1"},
+		}, c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+	assert.Equal(t, "2", os.Getenv("WATCH_A"))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestWatch_NewInclude(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("WATCH_INC=1\n"), 0o644)
+	frag := filepath.Join(t.TempDir(), "frag.env")
+	_ = os.WriteFile(frag, []byte("WATCH_INC=2\n"), 0o644)
+	defer os.Unsetenv("WATCH_INC")
+
+	udotEnv := &UdotEnv{Config: &Config{}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan map[string]Change, 1)
+	go func() { _ = udotEnv.Watch(ctx, func(c map[string]Change) { changed <- c }) }()
+
+	time.Sleep(100 * time.Millisecond)
+	_ = os.WriteFile(path+".tmp", []byte("#include "+frag+"\n"), 0o644)
+	_ = os.Rename(path+".tmp", path)
+	select {
+	case c := <-changed:
+		assert.Equal(t, map[string]Change{"WATCH_INC": {Kind: Modified, Old: "1", New: "2"}}, c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	// the directory of the included file is only watched from the last load
+	time.Sleep(100 * time.Millisecond)
+	_ = os.WriteFile(frag+".tmp", []byte("WATCH_INC=3\n"), 0o644)
+	_ = os.Rename(frag+".tmp", frag)
+	select {
+	case c := <-changed:
+		assert.Equal(t, map[string]Change{"WATCH_INC": {Kind: Modified, Old: "2", New: "3"}}, c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change of the included file reported")
+	}
+}
+
+func TestWatch_NotLoaded(t *testing.T) {
+	udotEnv := &UdotEnv{Config: &Config{}}
+	assert.EqualError(t, udotEnv.Watch(context.Background(), nil), "no files to watch, call Load first")
+}
+
+func TestWatch_Error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	_ = os.WriteFile(path, []byte("WATCH_ERR=1\n"), 0o644)
+	defer os.Unsetenv("WATCH_ERR")
+
+	errs := make(chan error, 1)
+	udotEnv := &UdotEnv{Config: &Config{OnReload: func(_ map[string]Change, err error) {
+		if err == nil {
+			return
+		}
+		select {
+		case errs <- err:
+		default:
+		}
+	}}, EnvParam: stringSlice{path}}
+	assert.NoError(t, udotEnv.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = udotEnv.Watch(ctx, nil) }()
+
+	time.Sleep(100 * time.Millisecond)
+	_ = os.WriteFile(path+".tmp", []byte("BROKEN\n"), 0o644)
+	_ = os.Rename(path+".tmp", path)
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrParse)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error reported")
+	}
+	assert.Equal(t, "1", os.Getenv("WATCH_ERR"))
+
+	// Watch keeps watching after an error
+	_ = os.WriteFile(path+".tmp", []byte("WATCH_ERR=2\n"), 0o644)
+	_ = os.Rename(path+".tmp", path)
+	assert.Eventually(t, func() bool { return os.Getenv("WATCH_ERR") == "2" }, 5*time.Second, 10*time.Millisecond)
+}